@@ -0,0 +1,148 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// BurpItem is an item of burp suite "save items" xml export
+type BurpItem struct {
+	Time           string    `xml:"time"`
+	URL            string    `xml:"url"`
+	Host           *BurpHost `xml:"host"`
+	Port           string    `xml:"port"`
+	Protocol       string    `xml:"protocol"`
+	Method         string    `xml:"method"`
+	Path           string    `xml:"path"`
+	Extension      string    `xml:"extension"`
+	Request        *BurpData `xml:"request"`
+	Status         string    `xml:"status"`
+	ResponseLength string    `xml:"responselength"`
+	MimeType       string    `xml:"mimetype"`
+	Response       *BurpData `xml:"response"`
+	Comment        string    `xml:"comment"`
+}
+
+type BurpHost struct {
+	IP   string `xml:"ip,attr"`
+	Name string `xml:",chardata"`
+}
+
+type BurpData struct {
+	Base64 bool   `xml:"base64,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// Bytes return the decoded request or response, nil if data is empty or broken
+func (d *BurpData) Bytes() []byte {
+	if d == nil {
+		return nil
+	}
+	if !d.Base64 {
+		return []byte(d.Value)
+	}
+	bs, err := base64.StdEncoding.DecodeString(strings.TrimSpace(d.Value))
+	if err != nil {
+		return nil
+	}
+	return bs
+}
+
+func (item *BurpItem) RawRequest() []byte {
+	return item.Request.Bytes()
+}
+
+func (item *BurpItem) RawResponse() []byte {
+	return item.Response.Bytes()
+}
+
+// ToResponse parse raw response into Response with hashes, return nil if item has no response
+func (item *BurpItem) ToResponse() *Response {
+	raw := item.RawResponse()
+	if len(raw) == 0 {
+		return nil
+	}
+	resp := NewResponseWithRaw(raw)
+	if resp == nil {
+		return nil
+	}
+	resp.Resp.Request = item.toRequest()
+	resp.Hash()
+	return resp
+}
+
+// toRequest build request from url and method of item, headers are taken from the raw request if it can be parsed
+func (item *BurpItem) toRequest() *http.Request {
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(item.RawRequest())))
+	if err != nil {
+		req = &http.Request{Header: make(http.Header)}
+	}
+	if item.Method != "" {
+		req.Method = item.Method
+	} else if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+	if u, err := url.Parse(item.URL); err == nil && u.Scheme != "" {
+		req.URL = u
+		req.Host = u.Host
+	}
+	return req
+}
+
+// BurpReader stream items from burp xml, large exports are not loaded into memory at once
+type BurpReader struct {
+	decoder *xml.Decoder
+}
+
+func NewBurpReader(r io.Reader) *BurpReader {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	return &BurpReader{decoder: decoder}
+}
+
+// Next return the next item, io.EOF if there is no more item
+func (br *BurpReader) Next() (*BurpItem, error) {
+	for {
+		token, err := br.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "item" {
+			item := &BurpItem{}
+			if err := br.decoder.DecodeElement(item, &start); err != nil {
+				return nil, err
+			}
+			return item, nil
+		}
+	}
+}
+
+func ParseBurpItems(r io.Reader) ([]*BurpItem, error) {
+	var items []*BurpItem
+	reader := NewBurpReader(r)
+	for {
+		item, err := reader.Next()
+		if err == io.EOF {
+			return items, nil
+		} else if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+}
+
+func ParseBurpFile(filename string) ([]*BurpItem, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseBurpItems(f)
+}
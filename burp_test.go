@@ -0,0 +1,61 @@
+package parsers
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestParseBurpItems(t *testing.T) {
+	response := base64.StdEncoding.EncodeToString([]byte("HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=utf-8\r\n\r\n<html><title>Burp</title></html>"))
+	export := `<?xml version="1.0"?>
+<!DOCTYPE items [
+<!ELEMENT items (item*)>
+<!ATTLIST items burpVersion CDATA "">
+]>
+<items burpVersion="2023.1" exportTime="Mon Jan 01 00:00:00 UTC 2024">
+  <item>
+    <time>Mon Jan 01 00:00:00 UTC 2024</time>
+    <url><![CDATA[http://example.com/]]></url>
+    <host ip="93.184.216.34">example.com</host>
+    <port>80</port>
+    <protocol>http</protocol>
+    <method><![CDATA[GET]]></method>
+    <path><![CDATA[/]]></path>
+    <request base64="true"><![CDATA[` + base64.StdEncoding.EncodeToString([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")) + `]]></request>
+    <status>200</status>
+    <responselength>90</responselength>
+    <mimetype>HTML</mimetype>
+    <response base64="true"><![CDATA[` + response + `]]></response>
+    <comment></comment>
+  </item>
+</items>`
+
+	items, err := ParseBurpItems(strings.NewReader(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expect 1 item, got %d", len(items))
+	}
+	item := items[0]
+	if item.Host.IP != "93.184.216.34" || item.Host.Name != "example.com" || item.Method != "GET" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	if !strings.HasPrefix(string(item.RawRequest()), "GET / HTTP/1.1") {
+		t.Errorf("unexpected request: %q", item.RawRequest())
+	}
+	resp := item.ToResponse()
+	if resp == nil || resp.Title != "Burp" || resp.Hashes == nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if u := resp.URL(); u == nil || u.String() != "http://example.com/" || resp.Resp.Request.Method != "GET" || resp.Resp.Request.Host != "example.com" {
+		t.Errorf("unexpected request: %+v", resp.Resp.Request)
+	}
+
+	item.URL, item.Method, item.Request = "https://example.com/login", "POST", nil
+	resp = item.ToResponse()
+	if u := resp.URL(); u == nil || u.Scheme != "https" || resp.Resp.Request.Method != "POST" || !resp.IsTLS() {
+		t.Errorf("unexpected request of https item: %+v", resp.Resp.Request)
+	}
+}
@@ -0,0 +1,255 @@
+package parsers
+
+import (
+	"bytes"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"github.com/chainreactors/utils/encode"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CertExpiringDays certificate expires within the days is reported as expiring
+var CertExpiringDays = 30
+
+// tls version and cipher suite names, kept as a table instead of tls.CipherSuiteName for older go
+var tlsVersionNames = map[uint16]string{
+	0x0300:           "SSLv3",
+	tls.VersionTLS10: "TLS1.0",
+	tls.VersionTLS11: "TLS1.1",
+	tls.VersionTLS12: "TLS1.2",
+	0x0304:           "TLS1.3",
+}
+
+var cipherSuiteNames = map[uint16]string{
+	0x0005: "TLS_RSA_WITH_RC4_128_SHA",
+	0x000a: "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+	0x002f: "TLS_RSA_WITH_AES_128_CBC_SHA",
+	0x0035: "TLS_RSA_WITH_AES_256_CBC_SHA",
+	0x003c: "TLS_RSA_WITH_AES_128_CBC_SHA256",
+	0x009c: "TLS_RSA_WITH_AES_128_GCM_SHA256",
+	0x009d: "TLS_RSA_WITH_AES_256_GCM_SHA384",
+	0xc007: "TLS_ECDHE_ECDSA_WITH_RC4_128_SHA",
+	0xc009: "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
+	0xc00a: "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
+	0xc011: "TLS_ECDHE_RSA_WITH_RC4_128_SHA",
+	0xc012: "TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA",
+	0xc013: "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+	0xc014: "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+	0xc023: "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256",
+	0xc027: "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256",
+	0xc02f: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	0xc02b: "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	0xc030: "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	0xc02c: "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	0xcca8: "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	0xcca9: "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+	0x1301: "TLS_AES_128_GCM_SHA256",
+	0x1302: "TLS_AES_256_GCM_SHA384",
+	0x1303: "TLS_CHACHA20_POLY1305_SHA256",
+}
+
+func TLSVersionName(version uint16) string {
+	if name, ok := tlsVersionNames[version]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04X", version)
+}
+
+func CipherSuiteName(id uint16) string {
+	if name, ok := cipherSuiteNames[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04X", id)
+}
+
+// CertInfo metadata of the leaf certificate and the tls connection
+type CertInfo struct {
+	SubjectCN          string    `json:"subject_cn"`
+	SubjectOrg         []string  `json:"subject_org,omitempty"`
+	IssuerCN           string    `json:"issuer_cn"`
+	IssuerOrg          []string  `json:"issuer_org,omitempty"`
+	DNSNames           []string  `json:"dns_names,omitempty"`
+	IPAddresses        []string  `json:"ip_addresses,omitempty"`
+	NotBefore          time.Time `json:"not_before"`
+	NotAfter           time.Time `json:"not_after"`
+	Serial             string    `json:"serial"`
+	SignatureAlgorithm string    `json:"signature_algorithm"`
+	PublicKeyAlgorithm string    `json:"public_key_algorithm"`
+	KeySize            int       `json:"key_size,omitempty"`
+	SHA1               string    `json:"sha1"`
+	SHA256             string    `json:"sha256"`
+	ChainLength        int       `json:"chain_length"`
+	SelfSigned         bool      `json:"self_signed,omitempty"`
+	ServerName         string    `json:"server_name,omitempty"` // sni sent by client
+	HostnameMismatch   bool      `json:"hostname_mismatch,omitempty"`
+	TLSVersion         string    `json:"tls_version"`
+	CipherSuite        string    `json:"cipher_suite"`
+}
+
+// NewCertInfo return nil if state is nil or no peer certificate presented
+func NewCertInfo(state *tls.ConnectionState) *CertInfo {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	info := NewCertInfoWithCert(state.PeerCertificates[0])
+	info.ChainLength = len(state.PeerCertificates)
+	info.TLSVersion = TLSVersionName(state.Version)
+	info.CipherSuite = CipherSuiteName(state.CipherSuite)
+	info.ServerName = state.ServerName
+	if state.ServerName != "" {
+		info.HostnameMismatch = state.PeerCertificates[0].VerifyHostname(state.ServerName) != nil
+	}
+	return info
+}
+
+func NewCertInfoWithCert(cert *x509.Certificate) *CertInfo {
+	sha1sum := sha1.Sum(cert.Raw)
+	sha256sum := sha256.Sum256(cert.Raw)
+	info := &CertInfo{
+		SubjectCN:          cert.Subject.CommonName,
+		SubjectOrg:         cert.Subject.Organization,
+		IssuerCN:           cert.Issuer.CommonName,
+		IssuerOrg:          cert.Issuer.Organization,
+		DNSNames:           cert.DNSNames,
+		NotBefore:          cert.NotBefore,
+		NotAfter:           cert.NotAfter,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		PublicKeyAlgorithm: cert.PublicKeyAlgorithm.String(),
+		KeySize:            publicKeySize(cert.PublicKey),
+		SHA1:               hex.EncodeToString(sha1sum[:]),
+		SHA256:             hex.EncodeToString(sha256sum[:]),
+		ChainLength:        1,
+	}
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	if cert.SerialNumber != nil {
+		info.Serial = strings.ToUpper(cert.SerialNumber.Text(16))
+	}
+	// CheckSignatureFrom requires the parent to be a ca, self-signed leaf usually is not
+	if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		info.SelfSigned = cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+	}
+	return info
+}
+
+func publicKeySize(pub interface{}) int {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return key.Curve.Params().BitSize
+	case *dsa.PublicKey:
+		return key.P.BitLen()
+	default:
+		return 0
+	}
+}
+
+// Hosts return SANs, dns names and ip addresses
+func (c *CertInfo) Hosts() []string {
+	hosts := make([]string, 0, len(c.DNSNames)+len(c.IPAddresses))
+	hosts = append(hosts, c.DNSNames...)
+	return append(hosts, c.IPAddresses...)
+}
+
+func (c *CertInfo) Expired() bool {
+	return time.Now().After(c.NotAfter)
+}
+
+// Findings report expired, not yet valid, expiring, self-signed, weak key, weak signature and legacy tls version
+func (c *CertInfo) Findings() Findings {
+	var findings Findings
+	now := time.Now()
+	switch {
+	case now.After(c.NotAfter):
+		findings = append(findings, NewFinding("cert-expired", SeverityHigh, c.NotAfter.Format("2006-01-02")))
+	case now.Before(c.NotBefore):
+		findings = append(findings, NewFinding("cert-not-yet-valid", SeverityMedium, c.NotBefore.Format("2006-01-02")))
+	case now.AddDate(0, 0, CertExpiringDays).After(c.NotAfter):
+		findings = append(findings, NewFinding("cert-expiring", SeverityLow, c.NotAfter.Format("2006-01-02")))
+	}
+	if c.SelfSigned {
+		findings = append(findings, NewFinding("cert-self-signed", SeverityMedium, c.SubjectCN))
+	}
+	if c.HostnameMismatch {
+		findings = append(findings, NewFinding("cert-hostname-mismatch", SeverityMedium, c.ServerName))
+	}
+	switch c.PublicKeyAlgorithm {
+	case "RSA", "DSA":
+		if c.KeySize > 0 && c.KeySize < 2048 {
+			findings = append(findings, NewFinding("cert-weak-key", SeverityMedium, fmt.Sprintf("%s %d", c.PublicKeyAlgorithm, c.KeySize)))
+		}
+	case "ECDSA":
+		if c.KeySize > 0 && c.KeySize < 224 {
+			findings = append(findings, NewFinding("cert-weak-key", SeverityMedium, fmt.Sprintf("%s %d", c.PublicKeyAlgorithm, c.KeySize)))
+		}
+	}
+	if sig := strings.ToUpper(c.SignatureAlgorithm); strings.Contains(sig, "MD5") || (strings.Contains(sig, "SHA1") && !c.SelfSigned) {
+		findings = append(findings, NewFinding("cert-weak-signature", SeverityMedium, c.SignatureAlgorithm))
+	}
+	switch c.TLSVersion {
+	case "SSLv3", "TLS1.0", "TLS1.1":
+		findings = append(findings, NewFinding("legacy-tls-version", SeverityLow, c.TLSVersion))
+	}
+	return findings
+}
+
+// JA3SRaw build `version,cipher,extensions` of server hello from the connection state.
+// crypto/tls does not expose the raw server hello, so extensions are inferred from what was negotiated:
+// supported_versions(43) and key_share(51) for tls1.3, alpn(16), and status_request(5), sct(18) below tls1.3.
+// it is stable for the same server stack, but not always identical to the ja3s of a packet capture
+func JA3SRaw(state *tls.ConnectionState) string {
+	if state == nil {
+		return ""
+	}
+	version := state.Version
+	var exts []int
+	if version >= 0x0304 {
+		// legacy_version of tls1.3 server hello is always tls1.2
+		version = tls.VersionTLS12
+		exts = append(exts, 43, 51)
+	} else {
+		if len(state.OCSPResponse) > 0 {
+			exts = append(exts, 5)
+		}
+		if len(state.SignedCertificateTimestamps) > 0 {
+			exts = append(exts, 18)
+		}
+	}
+	if state.NegotiatedProtocol != "" {
+		exts = append(exts, 16)
+	}
+	sort.Ints(exts)
+	extStrs := make([]string, len(exts))
+	for i, ext := range exts {
+		extStrs[i] = strconv.Itoa(ext)
+	}
+	return fmt.Sprintf("%d,%d,%s", version, state.CipherSuite, strings.Join(extStrs, "-"))
+}
+
+// JA3S return md5 of JA3SRaw, empty if not tls
+func JA3S(state *tls.ConnectionState) string {
+	if state == nil {
+		return ""
+	}
+	return encode.Md5Hash([]byte(JA3SRaw(state)))
+}
+
+// SetTLS fill Cert and SSLHost from the tls connection state, do nothing if no certificate
+func (content *Content) SetTLS(state *tls.ConnectionState) {
+	if cert := NewCertInfo(state); cert != nil {
+		content.Cert = cert
+		content.SSLHost = cert.DNSNames
+	}
+}
@@ -0,0 +1,76 @@
+package parsers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNewCertInfo(t *testing.T) {
+	if NewCertInfo(&tls.ConnectionState{}) != nil {
+		t.Error("expect nil without peer certificates")
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0xabc),
+		Subject:      pkix.Name{CommonName: "example.com", Organization: []string{"Example"}},
+		DNSNames:     []string{"example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().AddDate(-2, 0, 0),
+		NotAfter:     time.Now().AddDate(-1, 0, 0),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	info := NewCertInfo(&tls.ConnectionState{
+		Version:          tls.VersionTLS12,
+		CipherSuite:      tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		ServerName:       "other.com",
+		PeerCertificates: []*x509.Certificate{cert},
+	})
+	if info.SubjectCN != "example.com" || info.IssuerCN != "example.com" || info.Serial != "ABC" || info.KeySize != 1024 {
+		t.Errorf("unexpected cert info %+v", info)
+	}
+	if len(info.Hosts()) != 2 || info.Hosts()[1] != "127.0.0.1" {
+		t.Errorf("unexpected hosts %v", info.Hosts())
+	}
+	if !info.SelfSigned || !info.Expired() || !info.HostnameMismatch {
+		t.Errorf("expect self-signed, expired and mismatched, got %+v", info)
+	}
+	if info.TLSVersion != "TLS1.2" || info.CipherSuite != "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256" {
+		t.Errorf("unexpected connection %s %s", info.TLSVersion, info.CipherSuite)
+	}
+	findings := info.Findings()
+	for _, name := range []string{"cert-expired", "cert-self-signed", "cert-hostname-mismatch", "cert-weak-key"} {
+		if !findings.Has(name) {
+			t.Errorf("expect finding %s, got %s", name, findings.String())
+		}
+	}
+}
+
+func TestJA3SRaw(t *testing.T) {
+	if JA3S(nil) != "" {
+		t.Error("expect empty ja3s without tls")
+	}
+	state := &tls.ConnectionState{Version: 0x0304, CipherSuite: 0x1301, NegotiatedProtocol: "h2"}
+	if raw := JA3SRaw(state); raw != "771,4865,16-43-51" {
+		t.Errorf("unexpected ja3s raw %s", raw)
+	}
+	state = &tls.ConnectionState{Version: tls.VersionTLS12, CipherSuite: 0xc02f, OCSPResponse: []byte{1}}
+	if raw := JA3SRaw(state); raw != "771,49199,5" {
+		t.Errorf("unexpected ja3s raw %s", raw)
+	}
+}
@@ -0,0 +1,252 @@
+package parsers
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chainreactors/utils/httputils"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16LE = []byte{0xff, 0xfe}
+	bomUTF16BE = []byte{0xfe, 0xff}
+)
+
+// charsets map normalized charset label to decoder, utf-8 and ascii compatible labels are not included
+var charsets = map[string]encoding.Encoding{
+	// gb2312 is a subset of gbk, decode it with gbk like browsers do
+	"gbk":        simplifiedchinese.GBK,
+	"gb2312":     simplifiedchinese.GBK,
+	"gb_2312-80": simplifiedchinese.GBK,
+	"euc-cn":     simplifiedchinese.GBK,
+	"x-gbk":      simplifiedchinese.GBK,
+	"cp936":      simplifiedchinese.GBK,
+	"gb18030":    simplifiedchinese.GB18030,
+	"hz-gb-2312": simplifiedchinese.HZGB2312,
+
+	"big5":       traditionalchinese.Big5,
+	"big5-hkscs": traditionalchinese.Big5,
+	"x-x-big5":   traditionalchinese.Big5,
+	"cp950":      traditionalchinese.Big5,
+
+	"shift_jis":   japanese.ShiftJIS,
+	"shift-jis":   japanese.ShiftJIS,
+	"sjis":        japanese.ShiftJIS,
+	"x-sjis":      japanese.ShiftJIS,
+	"ms_kanji":    japanese.ShiftJIS,
+	"windows-31j": japanese.ShiftJIS,
+	"cp932":       japanese.ShiftJIS,
+	"euc-jp":      japanese.EUCJP,
+	"x-euc-jp":    japanese.EUCJP,
+	"iso-2022-jp": japanese.ISO2022JP,
+
+	"euc-kr":         korean.EUCKR,
+	"ks_c_5601-1987": korean.EUCKR,
+	"windows-949":    korean.EUCKR,
+	"cp949":          korean.EUCKR,
+
+	"windows-1250": charmap.Windows1250,
+	"windows-1251": charmap.Windows1251,
+	"windows-1252": charmap.Windows1252,
+	"windows-1253": charmap.Windows1253,
+	"windows-1254": charmap.Windows1254,
+	"windows-1255": charmap.Windows1255,
+	"windows-1256": charmap.Windows1256,
+	"windows-1257": charmap.Windows1257,
+	"windows-1258": charmap.Windows1258,
+	"windows-874":  charmap.Windows874,
+	// latin1 is treated as windows-1252, the same as browsers
+	"iso-8859-1":  charmap.Windows1252,
+	"latin1":      charmap.Windows1252,
+	"iso-8859-2":  charmap.ISO8859_2,
+	"iso-8859-3":  charmap.ISO8859_3,
+	"iso-8859-4":  charmap.ISO8859_4,
+	"iso-8859-5":  charmap.ISO8859_5,
+	"iso-8859-6":  charmap.ISO8859_6,
+	"iso-8859-7":  charmap.ISO8859_7,
+	"iso-8859-8":  charmap.ISO8859_8,
+	"iso-8859-9":  charmap.Windows1254,
+	"iso-8859-10": charmap.ISO8859_10,
+	"iso-8859-13": charmap.ISO8859_13,
+	"iso-8859-14": charmap.ISO8859_14,
+	"iso-8859-15": charmap.ISO8859_15,
+	"iso-8859-16": charmap.ISO8859_16,
+	"koi8-r":      charmap.KOI8R,
+	"koi8-u":      charmap.KOI8U,
+
+	"utf-16":   unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+	"utf-16le": unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf-16be": unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+}
+
+// guessCharsets candidates of GuessCharset, ordered by priority when scores are equal
+var guessCharsets = []string{"gb18030", "big5", "shift_jis", "euc-kr", "windows-1252"}
+
+// NormalizeCharset lower and trim the charset label, and fold the aliases of utf-8 into "utf-8"
+func NormalizeCharset(charset string) string {
+	charset = strings.ToLower(strings.Trim(strings.TrimSpace(charset), "\"'; "))
+	if i := strings.IndexAny(charset, "\"'; "); i != -1 {
+		charset = charset[:i]
+	}
+	switch charset {
+	case "utf8", "utf-8", "unicode-1-1-utf-8", "us-ascii", "ascii":
+		return "utf-8"
+	case "iso8859-1", "iso_8859-1", "l1", "cp1252":
+		return "iso-8859-1"
+	}
+	return charset
+}
+
+// SniffBOM return the charset of byte order mark, empty if there is no BOM
+func SniffBOM(content []byte) string {
+	if bytes.HasPrefix(content, bomUTF8) {
+		return "utf-8"
+	} else if bytes.HasPrefix(content, bomUTF16LE) {
+		return "utf-16le"
+	} else if bytes.HasPrefix(content, bomUTF16BE) {
+		return "utf-16be"
+	}
+	return ""
+}
+
+// DetectCharset detect charset of http raw (or bare body), BOM > Content-Type header > meta tag > heuristic
+func DetectCharset(raw []byte) string {
+	body, _, ok := httputils.SplitHttpRaw(raw)
+	if !ok {
+		body = raw
+	}
+	if charset := SniffBOM(body); charset != "" {
+		return charset
+	}
+	if charset := MatchCharset(raw); charset != "" {
+		charset = NormalizeCharset(charset)
+		if _, ok := charsets[charset]; ok || charset == "utf-8" {
+			return charset
+		}
+	}
+	// binary content such as images must not be transcoded
+	if !strings.HasPrefix(http.DetectContentType(body), "text/") {
+		return ""
+	}
+	return GuessCharset(body)
+}
+
+// GuessCharset guess charset of undeclared content by scoring the decoded text of each candidate
+func GuessCharset(content []byte) string {
+	if utf8.Valid(content) {
+		return "utf-8"
+	}
+	if len(content) > 4096 {
+		content = content[:4096]
+	}
+
+	var best string
+	var bestScore int
+	for _, name := range guessCharsets {
+		decoded, err := charsets[name].NewDecoder().Bytes(content)
+		if err != nil {
+			continue
+		}
+		if score := charsetScore(name, decoded); best == "" || score > bestScore {
+			best, bestScore = name, score
+		}
+	}
+	return best
+}
+
+func charsetScore(charset string, decoded []byte) int {
+	var score int
+	var prev rune
+	for _, r := range string(decoded) {
+		switch {
+		case r == utf8.RuneError:
+			score -= 10
+		case r < 0x20 && r != '\t' && r != '\r' && r != '\n':
+			score -= 5
+		case r < 0x80:
+		case r >= 0x4e00 && r <= 0x9fff: // CJK unified ideographs
+			if charset == "gb18030" || charset == "big5" {
+				score += 2
+			} else if charset == "shift_jis" {
+				score++
+			}
+		case r >= 0x3040 && r <= 0x30ff: // hiragana and katakana
+			if charset == "shift_jis" {
+				score += 3
+			}
+		case r >= 0xac00 && r <= 0xd7af: // hangul syllables
+			if charset == "euc-kr" {
+				score += 3
+			}
+		case r >= 0xff61 && r <= 0xff9f: // halfwidth katakana, usually mojibake
+			score -= 2
+		case r >= 0x3000 && r <= 0x303f, r >= 0xff01 && r <= 0xff60: // CJK punctuation and fullwidth forms
+			score++
+		case r >= 0xa0 && r <= 0xff: // latin-1 supplement, accented letters in latin text are usually next to ascii letters
+			if charset == "windows-1252" && prev < 0x80 && prev > 0x20 {
+				score++
+			} else {
+				score--
+			}
+		default:
+			score--
+		}
+		prev = r
+	}
+	return score
+}
+
+// Any2utf8 decode content from encoder charset to utf-8, return content itself if charset unknown or decode failed
+func Any2utf8(encoder string, content []byte) []byte {
+	charset := NormalizeCharset(encoder)
+	if charset == "utf-8" {
+		return bytes.TrimPrefix(content, bomUTF8)
+	}
+	enc, ok := charsets[charset]
+	if !ok {
+		return content
+	}
+	decoded, err := enc.NewDecoder().Bytes(content)
+	if err != nil {
+		return content
+	}
+	return decoded
+}
+
+// DecodeRaw transcode the body of http raw into utf-8, the header is kept as is. bare body without header is transcoded as a whole
+func DecodeRaw(raw []byte) ([]byte, string) {
+	charset := DetectCharset(raw)
+	if charset == "" {
+		return raw, charset
+	}
+	body, header, ok := httputils.SplitHttpRaw(raw)
+	if !ok {
+		return Any2utf8(charset, raw), charset
+	}
+	if charset == "utf-8" && !bytes.HasPrefix(body, bomUTF8) {
+		return raw, charset
+	}
+	decoded := Any2utf8(charset, body)
+	buf := make([]byte, 0, len(header)+4+len(decoded))
+	buf = append(buf, header...)
+	buf = append(buf, "\r\n\r\n"...)
+	return append(buf, decoded...), charset
+}
+
+func Gbk2utf8(content []byte) []byte {
+	return Any2utf8("gbk", content)
+}
+
+func Gb23122utf8(content []byte) []byte {
+	return Any2utf8("gb2312", content)
+}
@@ -0,0 +1,88 @@
+package parsers
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func mustEncode(t *testing.T, enc encoding.Encoding, s string) []byte {
+	bs, err := enc.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
+
+func TestDetectCharset(t *testing.T) {
+	utf16 := append([]byte{0xff, 0xfe}, mustEncode(t, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), "<title>你好</title>")...)
+	cases := []struct {
+		name   string
+		raw    []byte
+		expect string
+	}{
+		{"header", append([]byte("HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=GB2312\r\n\r\n"), mustEncode(t, simplifiedchinese.GBK, "中文标题")...), "gb2312"},
+		{"meta", append([]byte("HTTP/1.1 200 OK\r\n\r\n<meta charset=\"Shift_JIS\">"), mustEncode(t, japanese.ShiftJIS, "日本語")...), "shift_jis"},
+		{"bom", append([]byte("HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=utf-8\r\n\r\n"), utf16...), "utf-16le"},
+		{"guess gbk", append([]byte("HTTP/1.1 200 OK\r\n\r\n"), mustEncode(t, simplifiedchinese.GBK, "这是一个没有声明编码的中文页面，用于测试。")...), "gb18030"},
+		{"guess sjis", append([]byte("HTTP/1.1 200 OK\r\n\r\n"), mustEncode(t, japanese.ShiftJIS, "これは日本語のページです。ログインしてください。")...), "shift_jis"},
+		{"guess euc-kr", append([]byte("HTTP/1.1 200 OK\r\n\r\n"), mustEncode(t, korean.EUCKR, "로그인 페이지입니다. 사용자 이름을 입력하세요.")...), "euc-kr"},
+		{"guess latin", []byte("HTTP/1.1 200 OK\r\n\r\nCaf\xe9 cr\xe8me br\xfbl\xe9e, d\xe9j\xe0 vu"), "windows-1252"},
+		{"utf8", []byte("HTTP/1.1 200 OK\r\n\r\n中文"), "utf-8"},
+		{"binary", []byte("HTTP/1.1 200 OK\r\n\r\n\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\xff\xfe"), ""},
+	}
+	for _, c := range cases {
+		if got := DetectCharset(c.raw); got != c.expect {
+			t.Errorf("%s: expect %q, got %q", c.name, c.expect, got)
+		}
+	}
+}
+
+func TestAny2utf8(t *testing.T) {
+	cases := map[string][]byte{
+		"gb2312":    mustEncode(t, simplifiedchinese.GBK, "管理后台"),
+		"GB18030":   mustEncode(t, simplifiedchinese.GB18030, "管理后台"),
+		"big5":      mustEncode(t, traditionalchinese.Big5, "管理後台"),
+		"euc-jp":    mustEncode(t, japanese.EUCJP, "管理画面"),
+		"utf-16be":  mustEncode(t, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), "管理"),
+		"\"utf-8\"": []byte("管理"),
+	}
+	expects := map[string]string{"gb2312": "管理后台", "GB18030": "管理后台", "big5": "管理後台", "euc-jp": "管理画面", "utf-16be": "管理", "\"utf-8\"": "管理"}
+	for charset, content := range cases {
+		if got := string(Any2utf8(charset, content)); got != expects[charset] {
+			t.Errorf("%s: expect %q, got %q", charset, expects[charset], got)
+		}
+	}
+}
+
+func TestNewContent_Decode(t *testing.T) {
+	header := "HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=gbk"
+	raw := append([]byte(header+"\r\n\r\n"), mustEncode(t, simplifiedchinese.GBK, "<title>管理后台</title>")...)
+	content := NewContent(raw)
+	if string(content.Body) != "<title>管理后台</title>" || string(content.Header) != header {
+		t.Errorf("unexpected content: %q %q", content.Header, content.Body)
+	}
+	if string(content.Origin) != string(raw) {
+		t.Error("origin bytes are modified")
+	}
+
+	// utf-16 body with bom, header stays ascii
+	header = "HTTP/1.1 200 OK\r\nServer: nginx"
+	raw = append([]byte(header+"\r\n\r\n"), mustEncode(t, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), "<title>管理</title>")...)
+	if utf16 := NewContent(raw); string(utf16.Header) != header || utf16.GetHeader("Server") != "nginx" || string(utf16.Body) != "<title>管理</title>" {
+		t.Errorf("unexpected utf-16 content: %q %q", utf16.Header, utf16.Body)
+	}
+
+	r := &Response{Content: content}
+	r.HashWith(HashOrigin)
+	origin := r.Hashes
+	r.HashWith(HashDecoded)
+	if origin.BodyMd5 == r.Hashes.BodyMd5 || origin.HeaderMd5 != r.Hashes.HeaderMd5 {
+		t.Error("unexpected hashes of different source")
+	}
+}
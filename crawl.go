@@ -0,0 +1,173 @@
+package parsers
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	LinkTagRegexp   = regexp.MustCompile(`(?is)<(?:a|area|link|script|img|iframe|frame|embed|source|video|audio|track|object|input|button|form)\b[^>]*>`)
+	FormRegexp      = regexp.MustCompile(`(?is)<form\b[^>]*>.*?(?:</form>|$)`)
+	FormInputRegexp = regexp.MustCompile(`(?is)<(input|select|textarea|button)\b[^>]*>`)
+	// EndpointRegexp quoted urls and paths in javascript, e.g. "/api/user", './a.js', `//cdn.com/x`, "user/list.do", "v1/login"
+	EndpointRegexp = regexp.MustCompile("[\"'`]((?:https?:)?//[\\w.-]+[^\"'`\\s<>]*|\\.{0,2}/[\\w.-][^\"'`\\s<>]*|[\\w-]+(?:/[\\w.-]+)*/[\\w-]+\\.(?:php|asp|aspx|ashx|jsp|jspx|do|action|json|html?|js|xml|cgi)(?:\\?[^\"'`\\s<>]*)?|(?:api|v\\d+)/[\\w./?=&-]+)[\"'`]")
+)
+
+// LinkAttributes attributes of tags that contain url
+var LinkAttributes = []string{"href", "src", "action", "formaction", "data"}
+
+type FormInput struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+}
+
+type Form struct {
+	Action  string       `json:"action"`
+	Method  string       `json:"method"`
+	Enctype string       `json:"enctype,omitempty"`
+	Inputs  []*FormInput `json:"inputs,omitempty"`
+}
+
+// CrawlResult urls found in body, all resolved against response url and deduplicated
+type CrawlResult struct {
+	Links     []string `json:"links,omitempty"`     // href, src, action of tags
+	Scripts   []string `json:"scripts,omitempty"`   // external javascript, for CrawlJS
+	Forms     []*Form  `json:"forms,omitempty"`     // forms with inputs
+	Endpoints []string `json:"endpoints,omitempty"` // urls and paths in inline javascript
+}
+
+// URLs return links and endpoints, deduplicated
+func (c *CrawlResult) URLs() []string {
+	return dedupStrings(append(append([]string{}, c.Links...), c.Endpoints...))
+}
+
+// Crawl extract links, forms, scripts and javascript endpoints from html body, resolved against `<base href>` and base url
+func Crawl(body []byte, base *url.URL) *CrawlResult {
+	content := string(body)
+	base = resolveBase(content, base)
+	result := &CrawlResult{}
+	for _, tag := range LinkTagRegexp.FindAllString(content, -1) {
+		attrs := ParseAttributes(tag)
+		for _, attr := range LinkAttributes {
+			link, ok := normalizeLink(base, attrs[attr])
+			if !ok {
+				continue
+			}
+			result.Links = append(result.Links, link)
+			if attr == "src" && strings.HasPrefix(strings.ToLower(tag), "<script") {
+				result.Scripts = append(result.Scripts, link)
+			}
+		}
+	}
+	result.Links = dedupStrings(result.Links)
+	result.Scripts = dedupStrings(result.Scripts)
+	result.Forms = ExtractForms(body, base)
+	for _, script := range ScriptRegexp.FindAllStringSubmatch(content, -1) {
+		result.Endpoints = append(result.Endpoints, CrawlJS([]byte(script[1]), base)...)
+	}
+	result.Endpoints = dedupStrings(result.Endpoints)
+	return result
+}
+
+// CrawlJS extract quoted urls and paths from javascript
+func CrawlJS(js []byte, base *url.URL) []string {
+	var endpoints []string
+	for _, m := range EndpointRegexp.FindAllStringSubmatch(string(js), -1) {
+		if link, ok := normalizeLink(base, m[1]); ok {
+			endpoints = append(endpoints, link)
+		}
+	}
+	return dedupStrings(endpoints)
+}
+
+// ExtractForms extract forms, action defaults to base url and method defaults to GET
+func ExtractForms(body []byte, base *url.URL) []*Form {
+	var forms []*Form
+	seen := make(map[string]bool)
+	for _, block := range FormRegexp.FindAllString(string(body), -1) {
+		attrs := ParseAttributes(block[:strings.IndexByte(block, '>')+1])
+		form := &Form{
+			Method:  strings.ToUpper(strings.TrimSpace(attrs["method"])),
+			Enctype: attrs["enctype"],
+		}
+		if form.Method == "" {
+			form.Method = "GET"
+		}
+		if action, ok := normalizeLink(base, attrs["action"]); ok {
+			form.Action = action
+		} else if base != nil {
+			form.Action = base.String()
+		}
+
+		key := form.Method + " " + form.Action
+		for _, m := range FormInputRegexp.FindAllStringSubmatch(block, -1) {
+			input := ParseAttributes(m[0])
+			if input["name"] == "" {
+				continue
+			}
+			typ := strings.ToLower(m[1])
+			if typ == "input" || typ == "button" {
+				if input["type"] != "" {
+					typ = strings.ToLower(input["type"])
+				} else if typ == "input" {
+					typ = "text"
+				} else {
+					typ = "submit"
+				}
+			}
+			form.Inputs = append(form.Inputs, &FormInput{Name: input["name"], Type: typ, Value: input["value"]})
+			key += " " + input["name"]
+		}
+		if !seen[key] {
+			seen[key] = true
+			forms = append(forms, form)
+		}
+	}
+	return forms
+}
+
+// normalizeLink resolve link against base and drop fragment, non-http links are ignored
+func normalizeLink(base *url.URL, link string) (string, bool) {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.HasPrefix(link, "#") {
+		return "", false
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", false
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		// javascript:, mailto:, data: ...
+		return "", false
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	u.Fragment = ""
+	return u.String(), true
+}
+
+func dedupStrings(ss []string) []string {
+	if len(ss) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(ss))
+	uniq := ss[:0]
+	for _, s := range ss {
+		if !seen[s] {
+			seen[s] = true
+			uniq = append(uniq, s)
+		}
+	}
+	return uniq
+}
+
+// Crawl extract urls from body resolved against response url, javascript body is treated as a whole script
+func (r *Response) Crawl() *CrawlResult {
+	if r.Kind() == JavaScriptKind {
+		return &CrawlResult{Endpoints: CrawlJS(r.Body, r.URL())}
+	}
+	return Crawl(r.Body, r.URL())
+}
@@ -0,0 +1,89 @@
+package parsers
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestCrawl(t *testing.T) {
+	base, _ := url.Parse("http://example.com/app/index.html")
+	body := []byte(`<html><head>
+<link rel="stylesheet" href="/static/main.css">
+<script src="js/app.js"></script>
+<script>
+var api = "/api/v1/users?id=1";
+fetch('user/list.do').then(r => r.json());
+var mime = "text/html", cdn = "//cdn.example.com/lib.js";
+</script></head>
+<body>
+<a href="http://other.com/#top">other</a>
+<a href="javascript:void(0)">noop</a>
+<a href="/static/main.css#x">dup</a>
+<form action="/login" method="post">
+<input type="text" name="username">
+<input type="password" name="password">
+<input type="hidden" name="token" value="abc">
+<select name="lang"></select>
+<button>submit</button>
+</form>
+<form><input name="q"></form>
+</body></html>`)
+
+	result := Crawl(body, base)
+	expectLinks := []string{"http://example.com/static/main.css", "http://example.com/app/js/app.js", "http://other.com/", "http://example.com/login"}
+	if len(result.Links) != len(expectLinks) {
+		t.Fatalf("unexpected links %v", result.Links)
+	}
+	for i, link := range expectLinks {
+		if result.Links[i] != link {
+			t.Errorf("expect %s, got %s", link, result.Links[i])
+		}
+	}
+	if len(result.Scripts) != 1 || result.Scripts[0] != "http://example.com/app/js/app.js" {
+		t.Errorf("unexpected scripts %v", result.Scripts)
+	}
+
+	expectEndpoints := []string{"http://example.com/api/v1/users?id=1", "http://example.com/app/user/list.do", "http://cdn.example.com/lib.js"}
+	if len(result.Endpoints) != len(expectEndpoints) {
+		t.Fatalf("unexpected endpoints %v", result.Endpoints)
+	}
+	for i, endpoint := range expectEndpoints {
+		if result.Endpoints[i] != endpoint {
+			t.Errorf("expect %s, got %s", endpoint, result.Endpoints[i])
+		}
+	}
+
+	if len(result.Forms) != 2 {
+		t.Fatalf("unexpected forms %v", result.Forms)
+	}
+	login := result.Forms[0]
+	if login.Action != "http://example.com/login" || login.Method != "POST" || len(login.Inputs) != 4 {
+		t.Errorf("unexpected login form %+v", login)
+	}
+	if login.Inputs[2].Type != "hidden" || login.Inputs[2].Value != "abc" || login.Inputs[3].Type != "select" {
+		t.Errorf("unexpected inputs %+v %+v", login.Inputs[2], login.Inputs[3])
+	}
+	if search := result.Forms[1]; search.Action != base.String() || search.Method != "GET" || search.Inputs[0].Type != "text" {
+		t.Errorf("unexpected search form %+v", search)
+	}
+}
+
+func TestCrawl_Base(t *testing.T) {
+	base, _ := url.Parse("http://example.com/app/")
+	body := []byte(`<html><head><base href="http://cdn.example.com/x/"></head>
+<body><a href="a.html">a</a>
+<form action="login.do"><input name="u"></form>
+<script>fetch("api/list.json")</script>
+</body></html>`)
+
+	result := Crawl(body, base)
+	if len(result.Links) != 2 || result.Links[0] != "http://cdn.example.com/x/a.html" || result.Links[1] != "http://cdn.example.com/x/login.do" {
+		t.Errorf("unexpected links %v", result.Links)
+	}
+	if len(result.Forms) != 1 || result.Forms[0].Action != "http://cdn.example.com/x/login.do" {
+		t.Errorf("unexpected forms %v", result.Forms)
+	}
+	if len(result.Endpoints) != 1 || result.Endpoints[0] != "http://cdn.example.com/x/api/list.json" {
+		t.Errorf("unexpected endpoints %v", result.Endpoints)
+	}
+}
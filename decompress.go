@@ -0,0 +1,109 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type decodedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// decodeContentEncoding wrap resp.Body with decoders of Content-Encoding, and drop Content-Encoding and Content-Length
+// from resp.Header, the same as transparent decompression of net/http. so Raw and Origin hold the decoded body,
+// the encoding and encoded size are recorded in Content instead. return the encodings and the reader counting encoded bytes,
+// response is left untouched if it is already decompressed or any of the encodings is unsupported
+func decodeContentEncoding(resp *http.Response) (string, *countReader) {
+	if resp == nil || resp.Body == nil || resp.Uncompressed {
+		return "", nil
+	}
+
+	var encodings []string
+	for _, v := range resp.Header["Content-Encoding"] {
+		for _, enc := range strings.Split(v, ",") {
+			enc = strings.ToLower(strings.TrimSpace(enc))
+			switch enc {
+			case "", "identity":
+			case "gzip", "x-gzip", "deflate", "br":
+				encodings = append(encodings, enc)
+			default:
+				return "", nil
+			}
+		}
+	}
+	if len(encodings) == 0 {
+		return "", nil
+	}
+
+	counter := &countReader{r: resp.Body}
+	var reader io.Reader = counter
+	// encodings are listed in the order in which they were applied
+	for i := len(encodings) - 1; i >= 0; i-- {
+		reader = newContentDecoder(encodings[i], reader)
+	}
+	resp.Body = &decodedBody{Reader: reader, Closer: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return strings.Join(encodings, ", "), counter
+}
+
+// newContentDecoder fall back to reading as is if the stream does not match the declared encoding
+func newContentDecoder(encoding string, r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	switch encoding {
+	case "gzip", "x-gzip":
+		if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+			if gr, err := gzip.NewReader(br); err == nil {
+				return gr
+			}
+		}
+	case "deflate":
+		// "deflate" is zlib wrapped by spec, but some servers send raw deflate
+		if h, _ := br.Peek(2); len(h) == 2 && h[0]&0x0f == 8 && (uint16(h[0])<<8|uint16(h[1]))%31 == 0 {
+			if zr, err := zlib.NewReader(br); err == nil {
+				return zr
+			}
+		}
+		if isRawDeflate(br) {
+			return flate.NewReader(br)
+		}
+	case "br":
+		return brotli.NewReader(br)
+	}
+	return br
+}
+
+// isRawDeflate raw deflate has no magic, so the buffered leading bytes are decompressed as a probe.
+// corrupt data means the body is not compressed at all
+func isRawDeflate(br *bufio.Reader) bool {
+	peeked, _ := br.Peek(br.Size())
+	if len(peeked) == 0 {
+		return false
+	}
+	_, err := io.Copy(ioutil.Discard, flate.NewReader(bytes.NewReader(peeked)))
+	_, corrupt := err.(flate.CorruptInputError)
+	return !corrupt
+}
@@ -0,0 +1,63 @@
+package parsers
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestNewResponseWithRaw_ContentEncoding(t *testing.T) {
+	body := []byte("<html><title>compressed</title></html>")
+	var gz, zl, fl, br bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write(body)
+	gw.Close()
+	zw := zlib.NewWriter(&zl)
+	zw.Write(body)
+	zw.Close()
+	fw, _ := flate.NewWriter(&fl, flate.DefaultCompression)
+	fw.Write(body)
+	fw.Close()
+	bw := brotli.NewWriter(&br)
+	bw.Write(body)
+	bw.Close()
+
+	chunked := fmt.Sprintf("%x\r\n%s\r\n0\r\n\r\n", gz.Len(), gz.Bytes())
+	cases := map[string][]byte{
+		"gzip":    append([]byte(fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Encoding: gzip\r\nContent-Length: %d\r\n\r\n", gz.Len())), gz.Bytes()...),
+		"deflate": append([]byte(fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Encoding: deflate\r\nContent-Length: %d\r\n\r\n", zl.Len())), zl.Bytes()...),
+		"raw":     append([]byte(fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Encoding: deflate\r\nContent-Length: %d\r\n\r\n", fl.Len())), fl.Bytes()...),
+		"br":      append([]byte(fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Encoding: br\r\nContent-Length: %d\r\n\r\n", br.Len())), br.Bytes()...),
+		"chunked": []byte("HTTP/1.1 200 OK\r\nContent-Encoding: gzip\r\nTransfer-Encoding: chunked\r\n\r\n" + chunked),
+	}
+	for name, raw := range cases {
+		resp := NewResponseWithRaw(raw)
+		if resp == nil {
+			t.Fatalf("%s: parse failed", name)
+		}
+		if !bytes.Equal(resp.Body, body) || resp.Title != "compressed" {
+			t.Errorf("%s: unexpected body %q", name, resp.Body)
+		}
+		if resp.Encoding == "" || resp.EncodedSize == 0 || bytes.Contains(resp.Header, []byte("Content-Encoding")) {
+			t.Errorf("%s: unexpected encoding %q, %d", name, resp.Encoding, resp.EncodedSize)
+		}
+	}
+}
+
+func TestNewResponseWithRaw_PlainFallback(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate"} {
+		raw := "HTTP/1.1 200 OK\r\nContent-Encoding: " + encoding + "\r\n\r\n<title>plain</title>"
+		resp := NewResponseWithRaw([]byte(raw))
+		if resp == nil {
+			t.Fatalf("%s: parse failed", encoding)
+		}
+		if string(resp.Body) != "<title>plain</title>" || resp.Title != "plain" {
+			t.Errorf("%s: plain body should be kept, got %q", encoding, resp.Body)
+		}
+	}
+}
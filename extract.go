@@ -2,15 +2,19 @@ package parsers
 
 import (
 	"fmt"
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/xpath"
 	"github.com/chainreactors/utils/encode"
 	"github.com/chainreactors/utils/iutils"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
 type Extracted struct {
-	Name          string   `json:"name"`
-	ExtractResult []string `json:"extract_result"`
+	Name          string              `json:"name"`
+	ExtractResult []string            `json:"extract_result"`
+	Values        []map[string]string `json:"values,omitempty"` // named groups of each match
 }
 
 func (e *Extracted) String() string {
@@ -42,23 +46,145 @@ func (es *Extracteds) Merge(other Extracteds) {
 	}
 }
 
+// extractor types, regex if empty
+const (
+	RegexExtractor   = "regex"
+	KeywordExtractor = "keyword"
+	JSONExtractor    = "json"
+	XPathExtractor   = "xpath"
+	CSSExtractor     = "css"
+	HeaderExtractor  = "header"
+)
+
 type Extractor struct {
-	Name            string           `json:"name"` // extractor name
-	Regexps         []string         `json:"regexps"`
-	Tags            []string         `json:"tags"`
-	CompiledRegexps []*regexp.Regexp `json:"-"`
-	Cases           []string         `json:"cases"`
-}
-
-func (e *Extractor) Compile() {
-	e.CompiledRegexps = make([]*regexp.Regexp, len(e.Regexps))
-	for i, r := range e.Regexps {
-		if parsed, ok := encode.DSLParserToString(iutils.ToString(r)); ok {
-			e.CompiledRegexps[i] = regexp.MustCompile(parsed)
-		} else {
-			e.CompiledRegexps[i] = regexp.MustCompile(iutils.ToString(r))
+	Name            string           `json:"name" yaml:"name"`                     // extractor name
+	Type            string           `json:"type,omitempty" yaml:"type,omitempty"` // regex, keyword, json, xpath, css or header, regex if empty
+	Regexps         []string         `json:"regexps" yaml:"regexps"`
+	Capture         string           `json:"capture,omitempty" yaml:"capture,omitempty"` // capture group index or name as result, whole match if empty
+	Keywords        []string         `json:"keywords,omitempty" yaml:"keywords,omitempty"`
+	JSONPaths       []string         `json:"json,omitempty" yaml:"json,omitempty"` // jsonpath like `$.data[*].token`, or json pointer like `/data/0/token`
+	XPaths          []string         `json:"xpath,omitempty" yaml:"xpath,omitempty"`
+	Selectors       []string         `json:"css,omitempty" yaml:"css,omitempty"`
+	Attribute       string           `json:"attribute,omitempty" yaml:"attribute,omitempty"` // attribute of xpath and css matched nodes, inner text if empty
+	Headers         []string         `json:"headers,omitempty" yaml:"headers,omitempty"`     // header names, case-insensitive
+	Tags            []string         `json:"tags" yaml:"tags"`
+	CompiledRegexps []*regexp.Regexp `json:"-" yaml:"-"`
+	Cases           []string         `json:"cases" yaml:"cases"` // examples each of which must be matched, see Test
+
+	jsonPaths []*JSONPath
+	xpaths    []*xpath.Expr
+	selectors []cascadia.Selector
+}
+
+// Compile compile all rules of the type, invalid ones are skipped and the first error is returned
+func (e *Extractor) Compile() error {
+	if errs := e.compile(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+func (e *Extractor) compile() []error {
+	var errs []error
+	addErr := func(i int, err error) {
+		errs = append(errs, &ExtractorError{Name: e.Name, Field: e.field(), Index: i, Err: err})
+	}
+	switch e.Type {
+	case "", RegexExtractor:
+		e.CompiledRegexps = make([]*regexp.Regexp, 0, len(e.Regexps))
+		for i := range e.Regexps {
+			r, err := regexp.Compile(e.pattern(i))
+			if err != nil {
+				addErr(i, err)
+				continue
+			}
+			if e.captureIndex(r) < 0 {
+				addErr(i, ErrNoCaptureGroup)
+				continue
+			}
+			e.CompiledRegexps = append(e.CompiledRegexps, r)
+		}
+	case KeywordExtractor, HeaderExtractor:
+	case JSONExtractor:
+		e.jsonPaths = make([]*JSONPath, 0, len(e.JSONPaths))
+		for i, path := range e.JSONPaths {
+			p, err := CompileJSONPath(path)
+			if err != nil {
+				addErr(i, err)
+				continue
+			}
+			e.jsonPaths = append(e.jsonPaths, p)
+		}
+	case XPathExtractor:
+		e.xpaths = make([]*xpath.Expr, 0, len(e.XPaths))
+		for i, path := range e.XPaths {
+			expr, err := xpath.Compile(path)
+			if err != nil {
+				addErr(i, err)
+				continue
+			}
+			e.xpaths = append(e.xpaths, expr)
+		}
+	case CSSExtractor:
+		e.selectors = make([]cascadia.Selector, 0, len(e.Selectors))
+		for i, selector := range e.Selectors {
+			sel, err := cascadia.Compile(selector)
+			if err != nil {
+				addErr(i, err)
+				continue
+			}
+			e.selectors = append(e.selectors, sel)
 		}
+	default:
+		errs = append(errs, &ExtractorError{Name: e.Name, Index: -1, Err: ErrUnknownType})
+	}
+	return errs
+}
+
+// rules return rules of the type, nil if type is unknown
+func (e *Extractor) rules() []string {
+	switch e.Type {
+	case "", RegexExtractor:
+		return e.Regexps
+	case KeywordExtractor:
+		return e.Keywords
+	case JSONExtractor:
+		return e.JSONPaths
+	case XPathExtractor:
+		return e.XPaths
+	case CSSExtractor:
+		return e.Selectors
+	case HeaderExtractor:
+		return e.Headers
+	default:
+		return nil
+	}
+}
+
+// field return the rule field name of the type in rule files
+func (e *Extractor) field() string {
+	switch e.Type {
+	case KeywordExtractor:
+		return "keywords"
+	case JSONExtractor:
+		return "json"
+	case XPathExtractor:
+		return "xpath"
+	case CSSExtractor:
+		return "css"
+	case HeaderExtractor:
+		return "headers"
+	default:
+		return "regexps"
+	}
+}
+
+// pattern return the i-th regexp, dsl is parsed
+func (e *Extractor) pattern(i int) string {
+	if parsed, ok := encode.DSLParserToString(iutils.ToString(e.Regexps[i])); ok {
+		return parsed
 	}
+	return iutils.ToString(e.Regexps[i])
 }
 
 func (e *Extractor) HasTag(tag string) bool {
@@ -71,49 +197,128 @@ func (e *Extractor) HasTag(tag string) bool {
 }
 
 func (e *Extractor) Extract(body string) *Extracted {
+	return e.extract(body, false)
+}
+
+func (e *Extractor) ExtractUnique(body string) *Extracted {
+	return e.extract(body, true)
+}
+
+// ExtractContent header extractor is applied on header of content, the others on body
+func (e *Extractor) ExtractContent(content *Content, unique bool) *Extracted {
+	if e.Type == HeaderExtractor {
+		return e.extractHeader(content.HeaderMap(), unique)
+	}
+	return e.extract(string(content.Body), unique)
+}
+
+func (e *Extractor) extract(body string, unique bool) *Extracted {
+	switch e.Type {
+	case KeywordExtractor:
+		return e.extractKeyword(body, unique)
+	case JSONExtractor:
+		return e.extractJSON(body, unique)
+	case XPathExtractor:
+		return e.extractXPath(body, unique)
+	case CSSExtractor:
+		return e.extractCSS(body, unique)
+	case HeaderExtractor:
+		return e.extractHeader(ParseHeader(rawHeader(body)), unique)
+	}
+
 	extracts := &Extracted{
 		Name: e.Name,
 	}
+	seen := make(map[string]bool)
 	for _, r := range e.CompiledRegexps {
-		matches := r.FindAllString(body, -1)
-		if len(matches) > 0 {
-			extracts.ExtractResult = append(extracts.ExtractResult, matches...)
+		capture := e.captureIndex(r)
+		if capture < 0 {
+			continue
+		}
+		names := r.SubexpNames()
+		for _, match := range r.FindAllStringSubmatch(body, -1) {
+			result := match[capture]
+			if result == "" {
+				continue
+			}
+			if unique {
+				// 去重
+				if seen[result] {
+					continue
+				}
+				seen[result] = true
+			}
+			extracts.ExtractResult = append(extracts.ExtractResult, result)
+
+			var values map[string]string
+			for i, name := range names {
+				if name == "" || match[i] == "" {
+					continue
+				}
+				if values == nil {
+					values = make(map[string]string)
+				}
+				values[name] = match[i]
+			}
+			if values != nil {
+				extracts.Values = append(extracts.Values, values)
+			}
 		}
 	}
 	return extracts
 }
 
-func (e *Extractor) ExtractUnique(body string) *Extracted {
-	extracts := &Extracted{
-		Name: e.Name,
+// captureIndex return index of Capture in r, -1 if r has no such group
+func (e *Extractor) captureIndex(r *regexp.Regexp) int {
+	if e.Capture == "" {
+		return 0
 	}
-	uniqueMatches := make(map[string]struct{})
-
-	for _, r := range e.CompiledRegexps {
-		matches := r.FindAllString(body, -1)
-		for _, match := range matches {
-			uniqueMatches[match] = struct{}{}
+	if i, err := strconv.Atoi(e.Capture); err == nil {
+		if i < 0 || i > r.NumSubexp() {
+			return -1
 		}
+		return i
 	}
-
-	// 直接存储去重后的结果
-	extracts.ExtractResult = make([]string, 0, len(uniqueMatches))
-	for match := range uniqueMatches {
-		extracts.ExtractResult = append(extracts.ExtractResult, match)
+	for i, name := range r.SubexpNames() {
+		if name == e.Capture {
+			return i
+		}
 	}
-
-	return extracts
+	return -1
 }
 
 type Extractors map[string][]*Extractor
 
+// ExtractContent the same as Extract, but header extractors are applied on the parsed header of content
+func (es Extractors) ExtractContent(content *Content, unique bool) (extracteds []*Extracted) {
+	seen := make(map[*Extractor]bool)
+	for _, extract := range es {
+		for _, e := range extract {
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+			if extracted := e.ExtractContent(content, unique); extracted.ExtractResult != nil {
+				extracteds = append(extracteds, extracted)
+			}
+		}
+	}
+	return extracteds
+}
+
 func (es Extractors) Extract(content string, unique bool) (extracteds []*Extracted) {
 	if len(content) == 0 {
 		return
 	}
 
+	// the same extractor may be indexed by name and tags
+	seen := make(map[*Extractor]bool)
 	for _, extract := range es {
 		for _, e := range extract {
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
 			var extracted *Extracted
 			if unique {
 				extracted = e.ExtractUnique(content)
@@ -0,0 +1,178 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NewExtractors index extractors by name and by each of tags, e.g. Extractors["url"], Extractors["secret"]
+func NewExtractors(extractors []*Extractor) Extractors {
+	es := make(Extractors)
+	for _, e := range extractors {
+		es.Add(e)
+	}
+	return es
+}
+
+// Add index extractor by name and tags
+func (es Extractors) Add(e *Extractor) {
+	es[e.Name] = append(es[e.Name], e)
+	for _, tag := range e.Tags {
+		if tag != e.Name {
+			es[tag] = append(es[tag], e)
+		}
+	}
+}
+
+func (es Extractors) Merge(other Extractors) {
+	for group, extractors := range other {
+		es[group] = append(es[group], extractors...)
+	}
+}
+
+// Filter return extractors with the tag, grouped as is
+func (es Extractors) Filter(tag string) Extractors {
+	filtered := make(Extractors)
+	for group, extractors := range es {
+		for _, e := range extractors {
+			if e.HasTag(tag) {
+				filtered[group] = append(filtered[group], e)
+			}
+		}
+	}
+	return filtered
+}
+
+// Test run Cases of extractor as self-test, every case should be matched. extractor should be compiled
+func (e *Extractor) Test() []error {
+	var errs []error
+	for i, c := range e.Cases {
+		if len(e.Extract(c).ExtractResult) == 0 {
+			errs = append(errs, &ExtractorError{Name: e.Name, Index: -1, Err: fmt.Errorf("cases[%d] not matched: %q", i, c)})
+		}
+	}
+	return errs
+}
+
+// Test run Cases of all extractors, the same extractor indexed by name and tags is tested once
+func (es Extractors) Test() []error {
+	var errs []error
+	seen := make(map[*Extractor]bool)
+	for _, group := range es.groups() {
+		for _, e := range es[group] {
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+			for _, err := range e.Test() {
+				err.(*ExtractorError).Group = group
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+// ExtractorSelfTest run Cases of extractors loaded by LoadExtractorsFile and LoadExtractorsFS,
+// unmatched cases are returned as errors along with the extractors. enabled by default, set false to skip
+var ExtractorSelfTest = true
+
+// ParseExtractorsJSON parse extractor pack, either a list of extractors indexed by NewExtractors, or a map of group to extractors.
+// extractors are compiled, compile errors are returned as ExtractorErrors along with the extractors,
+// invalid rules are skipped and the others are still usable. nil extractors if content can not be parsed
+func ParseExtractorsJSON(content []byte) (Extractors, error) {
+	content = bytes.TrimSpace(content)
+	es := make(Extractors)
+	if bytes.HasPrefix(content, []byte("[")) {
+		var extractors []*Extractor
+		if err := json.Unmarshal(content, &extractors); err != nil {
+			return nil, err
+		}
+		es = NewExtractors(extractors)
+	} else if err := json.Unmarshal(content, &es); err != nil {
+		return nil, err
+	}
+	return es, compileExtractors(es)
+}
+
+// ParseExtractorsYAML the same as ParseExtractorsJSON, in yaml
+func ParseExtractorsYAML(content []byte) (Extractors, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, err
+	}
+	es := make(Extractors)
+	if len(node.Content) == 0 {
+		return es, nil
+	}
+	if node.Content[0].Kind == yaml.SequenceNode {
+		var extractors []*Extractor
+		if err := node.Decode(&extractors); err != nil {
+			return nil, err
+		}
+		es = NewExtractors(extractors)
+	} else if err := node.Decode(&es); err != nil {
+		return nil, err
+	}
+	return es, compileExtractors(es)
+}
+
+// ParseExtractors choose parser by extension of filename, `.yaml` and `.yml` are parsed as yaml, others as json
+func ParseExtractors(filename string, content []byte) (Extractors, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return ParseExtractorsYAML(content)
+	default:
+		return ParseExtractorsJSON(content)
+	}
+}
+
+// LoadExtractorsFile load extractor pack from file, see ExtractorSelfTest.
+// errors of invalid rules and cases are returned as ExtractorErrors, the returned extractors are still usable
+func LoadExtractorsFile(filename string) (Extractors, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	es, errs := loadExtractors(filename, content)
+	if es == nil {
+		return nil, errs[0]
+	}
+	if len(errs) > 0 {
+		return es, errs
+	}
+	return es, nil
+}
+
+// loadExtractors parse pack and run self-test if ExtractorSelfTest, errors are marked with filename.
+// nil extractors if content can not be parsed
+func loadExtractors(filename string, content []byte) (Extractors, ExtractorErrors) {
+	es, err := ParseExtractors(filename, content)
+	if es == nil {
+		return nil, ExtractorErrors{fmt.Errorf("%s: %s", filename, err.Error())}
+	}
+	var errs ExtractorErrors
+	if err != nil {
+		errs = append(errs, err.(ExtractorErrors)...)
+	}
+	if ExtractorSelfTest {
+		errs = append(errs, es.Test()...)
+	}
+	for _, err := range errs {
+		err.(*ExtractorError).File = filename
+	}
+	return es, errs
+}
+
+func compileExtractors(es Extractors) error {
+	if errs := es.Compile(); len(errs) > 0 {
+		return ExtractorErrors(errs)
+	}
+	return nil
+}
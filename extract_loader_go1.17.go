@@ -0,0 +1,39 @@
+//go:build go1.17
+// +build go1.17
+
+package parsers
+
+import (
+	"io/fs"
+)
+
+// LoadExtractorsFS load and merge extractor packs matched by patterns from fsys, e.g. embed.FS.
+// a broken pack or rule does not stop loading the others, errors are returned as ExtractorErrors
+// along with the usable extractors, see LoadExtractorsFile
+func LoadExtractorsFS(fsys fs.FS, patterns ...string) (Extractors, error) {
+	es := make(Extractors)
+	var errs ExtractorErrors
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, filename := range matches {
+			content, err := fs.ReadFile(fsys, filename)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			pack, packErrs := loadExtractors(filename, content)
+			errs = append(errs, packErrs...)
+			if pack != nil {
+				es.Merge(pack)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return es, errs
+	}
+	return es, nil
+}
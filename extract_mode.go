@@ -0,0 +1,145 @@
+package parsers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/chainreactors/utils/httputils"
+	"golang.org/x/net/html"
+)
+
+// extractedAppender append results to Extracted, dedupe if unique
+type extractedAppender struct {
+	*Extracted
+	unique bool
+	seen   map[string]bool
+}
+
+func newExtractedAppender(name string, unique bool) *extractedAppender {
+	return &extractedAppender{
+		Extracted: &Extracted{Name: name},
+		unique:    unique,
+		seen:      make(map[string]bool),
+	}
+}
+
+func (a *extractedAppender) add(result string) {
+	if result == "" {
+		return
+	}
+	if a.unique {
+		if a.seen[result] {
+			return
+		}
+		a.seen[result] = true
+	}
+	a.ExtractResult = append(a.ExtractResult, result)
+}
+
+// rawBody return body of raw http response, or content itself if not a raw response
+func rawBody(content string) string {
+	if strings.HasPrefix(content, "HTTP/") {
+		if body, _, ok := httputils.SplitHttpRaw([]byte(content)); ok {
+			return string(body)
+		}
+	}
+	return content
+}
+
+// rawHeader return header of raw http response, or content itself if no body
+func rawHeader(content string) []byte {
+	if _, header, ok := httputils.SplitHttpRaw([]byte(content)); ok {
+		return header
+	}
+	return []byte(content)
+}
+
+// extractKeyword each occurrence of keyword is a result, case-sensitive
+func (e *Extractor) extractKeyword(body string, unique bool) *Extracted {
+	a := newExtractedAppender(e.Name, unique)
+	for _, keyword := range e.Keywords {
+		if keyword == "" {
+			continue
+		}
+		for n := strings.Count(body, keyword); n > 0; n-- {
+			a.add(keyword)
+		}
+	}
+	return a.Extracted
+}
+
+// extractJSON strings are returned as is, objects and arrays as compact json
+func (e *Extractor) extractJSON(body string, unique bool) *Extracted {
+	a := newExtractedAppender(e.Name, unique)
+	var doc interface{}
+	decoder := json.NewDecoder(strings.NewReader(rawBody(body)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return a.Extracted
+	}
+	for _, path := range e.jsonPaths {
+		for _, v := range path.Find(doc) {
+			if s, ok := jsonString(v); ok {
+				a.add(s)
+			}
+		}
+	}
+	return a.Extracted
+}
+
+func (e *Extractor) extractXPath(body string, unique bool) *Extracted {
+	a := newExtractedAppender(e.Name, unique)
+	if len(e.xpaths) == 0 {
+		return a.Extracted
+	}
+	doc, err := htmlquery.Parse(strings.NewReader(rawBody(body)))
+	if err != nil {
+		return a.Extracted
+	}
+	for _, expr := range e.xpaths {
+		for _, node := range htmlquery.QuerySelectorAll(doc, expr) {
+			a.add(e.nodeValue(node))
+		}
+	}
+	return a.Extracted
+}
+
+func (e *Extractor) extractCSS(body string, unique bool) *Extracted {
+	a := newExtractedAppender(e.Name, unique)
+	if len(e.selectors) == 0 {
+		return a.Extracted
+	}
+	doc, err := html.Parse(strings.NewReader(rawBody(body)))
+	if err != nil {
+		return a.Extracted
+	}
+	for _, sel := range e.selectors {
+		for _, node := range sel.MatchAll(doc) {
+			a.add(e.nodeValue(node))
+		}
+	}
+	return a.Extracted
+}
+
+// nodeValue return Attribute of node, or trimmed inner text if Attribute is empty.
+// attribute node selected by xpath like `//a/@href` returns its value
+func (e *Extractor) nodeValue(node *html.Node) string {
+	if e.Attribute != "" {
+		return htmlquery.SelectAttr(node, e.Attribute)
+	}
+	return strings.TrimSpace(htmlquery.InnerText(node))
+}
+
+// extractHeader every value of the headers is a result
+func (e *Extractor) extractHeader(header http.Header, unique bool) *Extracted {
+	a := newExtractedAppender(e.Name, unique)
+	for _, name := range e.Headers {
+		for _, value := range header[textproto.CanonicalMIMEHeaderKey(name)] {
+			a.add(value)
+		}
+	}
+	return a.Extracted
+}
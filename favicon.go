@@ -0,0 +1,181 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"hash"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/chainreactors/utils/httputils"
+	"github.com/twmb/murmur3"
+)
+
+// FaviconRels link rel tokens treated as favicon
+var FaviconRels = []string{"icon", "apple-touch-icon", "apple-touch-icon-precomposed", "mask-icon"}
+
+// DefaultFavicon is used if no favicon declared in html
+var DefaultFavicon = "/favicon.ico"
+
+// FaviconHash return shodan/fofa compatible icon hash, signed mmh3 of python base64.encodebytes(icon)
+func FaviconHash(icon []byte) string {
+	h := newMmh3Writer()
+	h.Write(icon)
+	return h.Sum()
+}
+
+// encodeBytes the same as python base64.encodebytes, newline after every 76 chars and at the end
+func encodeBytes(raw []byte) []byte {
+	var buf bytes.Buffer
+	lw := &lineWriter{w: &buf}
+	enc := base64.NewEncoder(base64.StdEncoding, lw)
+	enc.Write(raw)
+	enc.Close()
+	lw.Close()
+	return buf.Bytes()
+}
+
+var newline = []byte{'\n'}
+
+// lineWriter insert newline after every 76 bytes, and at the end if the last line is not empty.
+// if trailing is true, newline is always appended at the end, as encode.Mmh3Hash32 does
+type lineWriter struct {
+	w        io.Writer
+	col      int
+	trailing bool
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		k := 76 - lw.col
+		if k > len(p) {
+			k = len(p)
+		}
+		if _, err := lw.w.Write(p[:k]); err != nil {
+			return written, err
+		}
+		written += k
+		lw.col += k
+		p = p[k:]
+		if lw.col == 76 {
+			lw.col = 0
+			if _, err := lw.w.Write(newline); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (lw *lineWriter) Close() error {
+	if lw.col > 0 || lw.trailing {
+		lw.col = 0
+		_, err := lw.w.Write(newline)
+		return err
+	}
+	return nil
+}
+
+// mmh3Writer stream bytes into mmh3 of encodebytes, without keeping the base64 in memory
+type mmh3Writer struct {
+	h   hash.Hash32
+	lw  *lineWriter
+	enc io.WriteCloser
+}
+
+func newMmh3Writer() *mmh3Writer {
+	return newMmh3WriterWithTrailing(false)
+}
+
+// newMmh3WriterWithTrailing trailing newline is always written, the same as encode.Mmh3Hash32,
+// which differs from FaviconHash when length of input is a multiple of 57
+func newMmh3WriterWithTrailing(trailing bool) *mmh3Writer {
+	h := murmur3.New32()
+	lw := &lineWriter{w: h, trailing: trailing}
+	return &mmh3Writer{h: h, lw: lw, enc: base64.NewEncoder(base64.StdEncoding, lw)}
+}
+
+func (m *mmh3Writer) Write(p []byte) (int, error) {
+	return m.enc.Write(p)
+}
+
+// Sum flush the encoder, writer should not be used after Sum
+func (m *mmh3Writer) Sum() string {
+	m.enc.Close()
+	m.lw.Close()
+	return strconv.Itoa(int(int32(m.h.Sum32())))
+}
+
+// ExtractFavicons extract favicon hrefs from html `<link rel=icon>`, resolved against `<base href>` and base url.
+// return DefaultFavicon resolved against base if none declared, duplicated hrefs are removed
+func ExtractFavicons(body []byte, base *url.URL) []string {
+	content := string(body)
+	base = resolveBase(content, base)
+
+	var icons []string
+	seen := make(map[string]bool)
+	for _, tag := range LinkRegexp.FindAllString(content, -1) {
+		attrs := ParseAttributes(tag)
+		href := strings.TrimSpace(attrs["href"])
+		if href == "" || !isFaviconRel(attrs["rel"]) {
+			continue
+		}
+		if icon := resolveURL(base, href); !seen[icon] {
+			seen[icon] = true
+			icons = append(icons, icon)
+		}
+	}
+
+	if len(icons) == 0 && base != nil {
+		icons = append(icons, resolveURL(base, DefaultFavicon))
+	}
+	return icons
+}
+
+func isFaviconRel(rel string) bool {
+	for _, token := range strings.Fields(strings.ToLower(rel)) {
+		for _, r := range FaviconRels {
+			if token == r {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveURL return href itself if base is nil or href is invalid
+func resolveURL(base *url.URL, href string) string {
+	if base == nil {
+		return href
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(u).String()
+}
+
+// URL return the request url of response, nil if unknown
+func (r *Response) URL() *url.URL {
+	if r.Resp == nil || r.Resp.Request == nil {
+		return nil
+	}
+	return r.Resp.Request.URL
+}
+
+// Favicons return favicon urls declared in body, resolved against response url
+func (r *Response) Favicons() []string {
+	return ExtractFavicons(r.Body, r.URL())
+}
+
+// FaviconHash hash the body as favicon, the bytes as received are used if available
+func (content *Content) FaviconHash() string {
+	if content.Origin != nil {
+		body, _, _ := httputils.SplitHttpRaw(content.Origin)
+		return FaviconHash(body)
+	}
+	return FaviconHash(content.Body)
+}
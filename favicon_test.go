@@ -0,0 +1,49 @@
+package parsers
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/chainreactors/utils/encode"
+)
+
+func TestFaviconHash(t *testing.T) {
+	// the same as python base64.encodebytes
+	if got := string(encodeBytes(bytes.Repeat([]byte("a"), 57))); got != strings.Repeat("YWFh", 19)+"\n" {
+		t.Errorf("unexpected encoded %q", got)
+	}
+	if len(encodeBytes(nil)) != 0 || len(encodeBytes(bytes.Repeat([]byte("a"), 100))) != 138 {
+		t.Error("unexpected encoded length")
+	}
+
+	icon := bytes.Repeat([]byte{0x00, 0x01, 0xff}, 100)
+	if FaviconHash(icon) != encode.Mmh3Hash32(icon) {
+		t.Errorf("expect the same hash as Mmh3Hash32 when no boundary, got %s", FaviconHash(icon))
+	}
+}
+
+func TestExtractFavicons(t *testing.T) {
+	base, _ := url.Parse("http://example.com/app/index.html")
+	body := []byte(`<head>
+<link rel="stylesheet" href="a.css">
+<link rel="Shortcut Icon" href="/static/favicon.ico">
+<link href="img/touch.png" rel=apple-touch-icon>
+<link rel="icon" href="/static/favicon.ico">
+</head>`)
+	icons := ExtractFavicons(body, base)
+	if len(icons) != 2 || icons[0] != "http://example.com/static/favicon.ico" || icons[1] != "http://example.com/app/img/touch.png" {
+		t.Errorf("unexpected icons %v", icons)
+	}
+
+	icons = ExtractFavicons([]byte(`<base href="http://cdn.example.com/"><link rel=icon href=f.png>`), base)
+	if len(icons) != 1 || icons[0] != "http://cdn.example.com/f.png" {
+		t.Errorf("unexpected icons with base %v", icons)
+	}
+
+	icons = ExtractFavicons([]byte("<html></html>"), base)
+	if len(icons) != 1 || icons[0] != "http://example.com/favicon.ico" {
+		t.Errorf("expect default favicon, got %v", icons)
+	}
+}
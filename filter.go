@@ -0,0 +1,218 @@
+package parsers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filterOperators ordered so that two-char operators are tried before their one-char prefix
+var filterOperators = []string{"::", "==", "!:", "!=", ">=", "<=", "~=", ">", "<"}
+
+// FilterKeys keys supported by SprayResult.Get and SprayResult.Match, ParseFilterCondition rejects the others
+var FilterKeys = []string{
+	"url", "host", "content_type", "type", "kind", "title", "redirect",
+	"md5", "simhash", "mmh3", "ja3s", "cert_hash", "cert_sha256",
+	"stat", "status", "spend", "length", "sim", "distance", "source", "from", "unique", "depth",
+	"extract", "extracts", "finding", "findings", "client_redirect", "client_redirects",
+	"frame", "framework", "frameworks", "tag", "tags", "cpe", "fsb", "uri", "wfn", "full",
+}
+
+// FilterCondition is a single `key op value` expression, e.g. `status>=300` or `title~=(?i)login`
+type FilterCondition struct {
+	Key   string
+	Op    string
+	Value string
+	re    *regexp.Regexp
+}
+
+func ParseFilterCondition(s string) (*FilterCondition, error) {
+	s = strings.TrimSpace(s)
+	for i := 0; i < len(s); i++ {
+		for _, op := range filterOperators {
+			if !strings.HasPrefix(s[i:], op) {
+				continue
+			}
+			cond := &FilterCondition{
+				Key:   strings.ToLower(strings.TrimSpace(s[:i])),
+				Op:    op,
+				Value: unquoteFilterValue(strings.TrimSpace(s[i+len(op):])),
+			}
+			if cond.Key == "" {
+				return nil, fmt.Errorf("filter %q: missing key", s)
+			}
+			if !isFilterKey(cond.Key) {
+				return nil, fmt.Errorf("filter %q: unknown key %q, please input one of [%s]", s, cond.Key, strings.Join(FilterKeys, ", "))
+			}
+			if op == "~=" {
+				re, err := regexp.Compile(cond.Value)
+				if err != nil {
+					return nil, fmt.Errorf("filter %q: %s", s, err.Error())
+				}
+				cond.re = re
+			}
+			return cond, nil
+		}
+	}
+	return nil, fmt.Errorf("filter %q: illegal operator, please input one of [%s]", s, strings.Join(filterOperators, ", "))
+}
+
+// Match compare value with condition, numeric operators compare the number contained in value, e.g. "12ms"
+func (cond *FilterCondition) Match(value string) bool {
+	switch cond.Op {
+	case "::":
+		return strings.Contains(strings.ToLower(value), strings.ToLower(cond.Value))
+	case "!:":
+		return !strings.Contains(strings.ToLower(value), strings.ToLower(cond.Value))
+	case "==":
+		return strings.EqualFold(value, cond.Value)
+	case "!=":
+		return !strings.EqualFold(value, cond.Value)
+	case "~=":
+		if cond.re == nil {
+			re, err := regexp.Compile(cond.Value)
+			if err != nil {
+				return false
+			}
+			cond.re = re
+		}
+		return cond.re.MatchString(value)
+	}
+
+	n, ok := parseNumber(value)
+	if !ok {
+		return false
+	}
+	expect, ok := parseNumber(cond.Value)
+	if !ok {
+		return false
+	}
+	switch cond.Op {
+	case ">=":
+		return n >= expect
+	case "<=":
+		return n <= expect
+	case ">":
+		return n > expect
+	case "<":
+		return n < expect
+	default:
+		return false
+	}
+}
+
+func (cond *FilterCondition) String() string {
+	return cond.Key + cond.Op + cond.Value
+}
+
+func isFilterKey(key string) bool {
+	for _, k := range FilterKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// unquoteFilterValue strip a pair of surrounding quotes, e.g. `"a||b"`
+func unquoteFilterValue(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+// FilterQuery is a disjunction of conjunctions, `a && b || c` is parsed as [[a, b], [c]]
+// values containing `&&` or `||` must be quoted, e.g. `title~="a||b"`
+type FilterQuery [][]*FilterCondition
+
+func ParseFilterQuery(s string) (FilterQuery, error) {
+	groups, err := splitFilterQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	var query FilterQuery
+	for _, group := range groups {
+		var conds []*FilterCondition
+		for _, and := range group {
+			cond, err := ParseFilterCondition(and)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, cond)
+		}
+		query = append(query, conds)
+	}
+	return query, nil
+}
+
+// splitFilterQuery split query by `||` and `&&`, a quote right after an operator opens a value that is kept as is
+func splitFilterQuery(s string) ([][]string, error) {
+	var groups [][]string
+	var conds []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && endsWithFilterOperator(s[start:i]):
+			quote = c
+		case strings.HasPrefix(s[i:], "&&"):
+			conds = append(conds, s[start:i])
+			i++
+			start = i + 1
+		case strings.HasPrefix(s[i:], "||"):
+			groups = append(groups, append(conds, s[start:i]))
+			conds = nil
+			i++
+			start = i + 1
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("filter %q: unterminated quote", s)
+	}
+	return append(groups, append(conds, s[start:])), nil
+}
+
+func endsWithFilterOperator(s string) bool {
+	s = strings.TrimSpace(s)
+	for _, op := range filterOperators {
+		if strings.HasSuffix(s, op) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match return true if any of the conjunctions is fully matched by match
+func (q FilterQuery) Match(match func(cond *FilterCondition) bool) bool {
+	for _, conds := range q {
+		matched := true
+		for _, cond := range conds {
+			if !match(cond) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func parseNumber(s string) (int, bool) {
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '-'
+	})
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -3,9 +3,16 @@ module github.com/chainreactors/parsers
 go 1.10
 
 require (
+	github.com/andybalholm/brotli v1.1.1
+	github.com/andybalholm/cascadia v1.3.1
+	github.com/antchfx/htmlquery v1.3.0
+	github.com/antchfx/xpath v1.3.3
 	github.com/chainreactors/files v0.0.0-20231123083421-cea5b4ad18a8
 	github.com/chainreactors/fingers v0.0.0-20240702104653-a66e34aa41df
 	github.com/chainreactors/logs v0.0.0-20240207121836-c946f072f81f
 	github.com/chainreactors/utils v0.0.0-20240704062557-662d623b74f4
+	github.com/twmb/murmur3 v1.1.8
+	golang.org/x/net v0.21.0
 	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -70,8 +70,11 @@ type GOGOResult struct {
 	Frameworks common.Frameworks   `json:"frameworks,omitempty"`
 	Vulns      common.Vulns        `json:"vulns,omitempty"`
 	Extracteds map[string][]string `json:"extracted,omitempty"`
+	Findings   Findings            `json:"findings,omitempty"`
+	Redirects  ClientRedirects     `json:"client_redirects,omitempty"`
 	Title      string              `json:"title,omitempty"`
 	Midware    string              `json:"midware,omitempty"`
+	Hashes     *Hashes             `json:"hashes,omitempty"`
 }
 
 func (result *GOGOResult) IsHttp() bool {
@@ -167,6 +170,20 @@ func (result *GOGOResult) Get(key string) string {
 		}
 		s.WriteString(" ]")
 		return s.String()
+	case "finding", "findings":
+		return result.Findings.String()
+	case "client_redirect", "client_redirects":
+		return result.Redirects.String()
+	case "ja3s":
+		if result.Hashes != nil {
+			return result.Hashes.JA3S
+		}
+		return ""
+	case "cert_hash", "cert_sha256":
+		if result.Hashes != nil {
+			return result.Hashes.CertSHA256
+		}
+		return ""
 	default:
 		return ""
 	}
@@ -192,7 +209,7 @@ func (result *GOGOResult) ColorOutput() string {
 }
 
 func (result *GOGOResult) FullOutput() string {
-	s := fmt.Sprintf("[+] %s\t%s\t%s\t%s [%s] %s %s %s\n", result.GetURL(), result.Midware, result.Frameworks.String(), result.Host, result.Status, result.Title, result.Vulns.String(), result.GetExtractStat())
+	s := fmt.Sprintf("[+] %s\t%s\t%s\t%s [%s] %s %s %s %s %s\n", result.GetURL(), result.Midware, result.Frameworks.String(), result.Host, result.Status, result.Title, result.Vulns.String(), result.GetExtractStat(), result.Findings.String(), result.Redirects.String())
 	return s
 }
 
@@ -214,6 +231,8 @@ func (result *GOGOResult) CsvOutput() string {
 		result.Midware,
 		result.Frameworks.String(),
 		result.Vulns.String(),
+		result.Get("ja3s"),
+		result.Get("cert_hash"),
 	}
 	w.Write(record)
 	w.Flush()
@@ -419,7 +438,7 @@ func (rd *GOGOData) ToJson() string {
 
 func (rd *GOGOData) ToCsv() string {
 	var s strings.Builder
-	s.WriteString("ip,port,url,status,title,host,language,midware,frame,vuln,extract\n")
+	s.WriteString("ip,port,url,status,title,host,midware,frame,vuln,ja3s,cert_sha256\n")
 	for _, r := range rd.Data {
 		s.WriteString(r.CsvOutput())
 	}
@@ -0,0 +1,281 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// HAR 1.2, http://www.softwareishard.com/blog/har-12-spec/
+type HAR struct {
+	Log *HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string      `json:"version"`
+	Creator *HARCreator `json:"creator"`
+	Entries []*HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntry struct {
+	StartedDateTime string       `json:"startedDateTime"`
+	Time            float64      `json:"time"`
+	Request         *HARRequest  `json:"request"`
+	Response        *HARResponse `json:"response"`
+	Cache           struct{}     `json:"cache"`
+	Timings         *HARTimings  `json:"timings"`
+	Comment         string       `json:"comment,omitempty"`
+}
+
+type HARRequest struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*HARNameValue `json:"cookies"`
+	Headers     []*HARNameValue `json:"headers"`
+	QueryString []*HARNameValue `json:"queryString"`
+	HeadersSize int             `json:"headersSize"`
+	BodySize    int             `json:"bodySize"`
+}
+
+type HARResponse struct {
+	Status      int             `json:"status"`
+	StatusText  string          `json:"statusText"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*HARNameValue `json:"cookies"`
+	Headers     []*HARNameValue `json:"headers"`
+	Content     *HARContent     `json:"content"`
+	RedirectURL string          `json:"redirectURL"`
+	HeadersSize int             `json:"headersSize"`
+	BodySize    int             `json:"bodySize"`
+}
+
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func NewHAR() *HAR {
+	return &HAR{
+		Log: &HARLog{
+			Version: "1.2",
+			Creator: &HARCreator{Name: "chainreactors", Version: "1.0"},
+			Entries: []*HAREntry{},
+		},
+	}
+}
+
+// AddSprayResult append result as har entry, resp is optional, if provided, headers, body and redirect chain will be exported.
+// startedDateTime is taken from Date header of each response, the export time is used if absent
+func (h *HAR) AddSprayResult(result *SprayResult, resp *Response) {
+	now := time.Now()
+	started := now
+	if resp != nil {
+		h.addHistory(resp, now)
+		started = harStarted(resp.Content, now)
+	}
+
+	entry := newHAREntry(started, float64(result.Spended))
+	if resp != nil {
+		entry.Request = newHARRequest(resp.Resp, result.UrlString)
+		entry.Response = newHARResponse(resp.Content)
+	} else {
+		entry.Request = newHARRequest(nil, result.UrlString)
+		entry.Response = &HARResponse{
+			Status:      result.Status,
+			StatusText:  http.StatusText(result.Status),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []*HARNameValue{},
+			Headers:     []*HARNameValue{},
+			Content:     &HARContent{Size: result.BodyLength, MimeType: result.ContentType},
+			RedirectURL: result.RedirectURL,
+			HeadersSize: -1,
+			BodySize:    result.BodyLength,
+		}
+	}
+	if result.Status != 0 {
+		entry.Response.Status = result.Status
+	}
+	if entry.Response.RedirectURL == "" {
+		entry.Response.RedirectURL = result.RedirectURL
+	}
+	if result.Title != "" {
+		entry.Comment = result.Title
+	}
+	h.Log.Entries = append(h.Log.Entries, entry)
+}
+
+// AddResponse append response and its redirect chain as har entries, see AddSprayResult for startedDateTime
+func (h *HAR) AddResponse(resp *Response) {
+	now := time.Now()
+	h.addHistory(resp, now)
+
+	var u string
+	if parsed := resp.URL(); parsed != nil {
+		u = parsed.String()
+	}
+	entry := newHAREntry(harStarted(resp.Content, now), 0)
+	entry.Request = newHARRequest(resp.Resp, u)
+	entry.Response = newHARResponse(resp.Content)
+	h.Log.Entries = append(h.Log.Entries, entry)
+}
+
+// addHistory export redirect chain in chronological order
+func (h *HAR) addHistory(resp *Response, now time.Time) {
+	for _, hop := range resp.History {
+		entry := newHAREntry(harStarted(hop.Content, now), 0)
+		entry.Request = newHARRequest(hop.Resp, hop.URL)
+		entry.Response = newHARResponse(hop.Content)
+		h.Log.Entries = append(h.Log.Entries, entry)
+	}
+}
+
+func (h *HAR) Json() ([]byte, error) {
+	return json.Marshal(h)
+}
+
+// harStarted return Date header of the response, the time is at second precision of server clock. fallback if absent
+func harStarted(content *Content, fallback time.Time) time.Time {
+	if content == nil {
+		return fallback
+	}
+	if date, err := http.ParseTime(content.GetHeader("Date")); err == nil {
+		return date
+	}
+	return fallback
+}
+
+func newHAREntry(started time.Time, spend float64) *HAREntry {
+	return &HAREntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            spend,
+		Timings:         &HARTimings{Wait: spend},
+	}
+}
+
+func newHARRequest(resp *http.Response, u string) *HARRequest {
+	req := &HARRequest{
+		Method:      http.MethodGet,
+		URL:         u,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []*HARNameValue{},
+		Headers:     []*HARNameValue{},
+		QueryString: []*HARNameValue{},
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+	if parsed, err := url.Parse(u); err == nil {
+		for k, vs := range parsed.Query() {
+			for _, v := range vs {
+				req.QueryString = append(req.QueryString, &HARNameValue{Name: k, Value: v})
+			}
+		}
+		sort.Slice(req.QueryString, func(i, j int) bool {
+			return req.QueryString[i].Name < req.QueryString[j].Name
+		})
+	}
+
+	if resp == nil || resp.Request == nil {
+		return req
+	}
+	r := resp.Request
+	if r.Method != "" {
+		req.Method = r.Method
+	}
+	if r.Proto != "" {
+		req.HTTPVersion = r.Proto
+	}
+	keys := make([]string, 0, len(r.Header))
+	for k := range r.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range r.Header[k] {
+			req.Headers = append(req.Headers, &HARNameValue{Name: k, Value: v})
+		}
+	}
+	for _, c := range r.Cookies() {
+		req.Cookies = append(req.Cookies, &HARNameValue{Name: c.Name, Value: c.Value})
+	}
+	return req
+}
+
+func newHARResponse(content *Content) *HARResponse {
+	resp := &HARResponse{
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []*HARNameValue{},
+		Headers:     []*HARNameValue{},
+		Content:     &HARContent{},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	if content == nil {
+		return resp
+	}
+
+	lines := strings.Split(string(content.Header), "\r\n")
+	if status := strings.SplitN(lines[0], " ", 3); len(status) >= 2 {
+		resp.HTTPVersion = status[0]
+		resp.Status, _ = strconv.Atoi(status[1])
+		if len(status) == 3 {
+			resp.StatusText = status[2]
+		}
+	}
+	for _, line := range lines[1:] {
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		name, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		resp.Headers = append(resp.Headers, &HARNameValue{Name: name, Value: value})
+		switch strings.ToLower(name) {
+		case "location":
+			resp.RedirectURL = value
+		case "content-type":
+			resp.Content.MimeType = value
+		}
+	}
+	for _, c := range content.Cookies() {
+		resp.Cookies = append(resp.Cookies, &HARNameValue{Name: c.Name, Value: c.Value})
+	}
+
+	resp.HeadersSize = len(content.Header) + 4
+	resp.BodySize = len(content.Body)
+	resp.Content.Size = len(content.Body)
+	if utf8.Valid(content.Body) {
+		resp.Content.Text = string(content.Body)
+	} else {
+		resp.Content.Text = base64.StdEncoding.EncodeToString(content.Body)
+		resp.Content.Encoding = "base64"
+	}
+	if resp.Content.MimeType == "" && len(bytes.TrimSpace(content.Body)) > 0 {
+		resp.Content.MimeType = http.DetectContentType(content.Body)
+	}
+	return resp
+}
@@ -0,0 +1,107 @@
+package parsers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHAR_AddSprayResult(t *testing.T) {
+	har := NewHAR()
+	har.AddSprayResult(&SprayResult{
+		UrlString:   "http://example.com/admin?b=2&a=1",
+		Status:      302,
+		RedirectURL: "/login",
+		BodyLength:  10,
+		ContentType: "text/html",
+		Spended:     15,
+		Title:       "admin",
+	}, nil)
+
+	raw := "HTTP/1.1 200 OK\r\nDate: Mon, 01 Jan 2024 00:00:00 GMT\r\nContent-Type: application/octet-stream\r\nSet-Cookie: sid=abc; HttpOnly\r\n\r\n\x89PNG\x00\xff"
+	resp := NewResponseWithRaw([]byte(raw))
+	har.AddSprayResult(&SprayResult{UrlString: "http://example.com/file", Status: 200}, resp)
+
+	if len(har.Log.Entries) != 2 {
+		t.Fatalf("expect 2 entries, got %d", len(har.Log.Entries))
+	}
+	entry := har.Log.Entries[0]
+	if entry.Response.Status != 302 || entry.Response.RedirectURL != "/login" || entry.Response.Content.Size != 10 || entry.Time != 15 || entry.Comment != "admin" {
+		t.Errorf("unexpected entry without response %+v", entry.Response)
+	}
+	if qs := entry.Request.QueryString; len(qs) != 2 || qs[0].Name != "a" || qs[1].Name != "b" {
+		t.Errorf("unexpected query string %v", qs)
+	}
+
+	entry = har.Log.Entries[1]
+	if entry.StartedDateTime != "2024-01-01T00:00:00Z" {
+		t.Errorf("expect Date header as startedDateTime, got %s", entry.StartedDateTime)
+	}
+	if c := entry.Response.Content; c.Encoding != "base64" || c.Text != "iVBORwD/" || c.Size != 6 {
+		t.Errorf("unexpected binary content %+v", c)
+	}
+	if cookies := entry.Response.Cookies; len(cookies) != 1 || cookies[0].Name != "sid" || cookies[0].Value != "abc" {
+		t.Errorf("unexpected cookies %v", cookies)
+	}
+	if _, err := har.Json(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHAR_AddResponse(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login", http.StatusFound)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1"})
+		w.Write([]byte("<title>login</title>"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+	req.Header.Set("Cookie", "lang=en")
+	raw, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	har := NewHAR()
+	har.AddResponse(NewResponse(raw, 0))
+
+	if len(har.Log.Entries) != 2 {
+		t.Fatalf("expect redirect and final entries, got %d", len(har.Log.Entries))
+	}
+	first, final := har.Log.Entries[0], har.Log.Entries[1]
+	if first.Request.URL != server.URL+"/" || first.Response.Status != 302 || first.Response.RedirectURL != "/login" {
+		t.Errorf("unexpected redirect entry %+v %+v", first.Request, first.Response)
+	}
+	if final.Request.URL != server.URL+"/login" || final.Response.Status != 200 || final.Response.Content.Text != "<title>login</title>" {
+		t.Errorf("unexpected final entry %+v %+v", final.Request, final.Response)
+	}
+	if cookies := first.Request.Cookies; len(cookies) != 1 || cookies[0].Name != "lang" {
+		t.Errorf("unexpected request cookies %v", cookies)
+	}
+	if cookies := final.Response.Cookies; len(cookies) != 1 || cookies[0].Value != "s1" {
+		t.Errorf("unexpected response cookies %v", cookies)
+	}
+	bs, _ := json.Marshal(har)
+	var decoded HAR
+	if err := json.Unmarshal(bs, &decoded); err != nil || len(decoded.Log.Entries) != 2 {
+		t.Errorf("unexpected json %s", bs)
+	}
+}
+
+func TestHAR_AddResponseWithoutURL(t *testing.T) {
+	item := &BurpItem{Response: &BurpData{Value: "HTTP/1.1 200 OK\r\n\r\nok"}}
+	resp := item.ToResponse()
+	if resp == nil || resp.Resp.Request == nil || resp.Resp.Request.URL != nil {
+		t.Fatalf("expect request without url, got %+v", resp)
+	}
+	har := NewHAR()
+	har.AddResponse(resp)
+	if len(har.Log.Entries) != 1 || har.Log.Entries[0].Request.URL != "" || har.Log.Entries[0].Response.Status != 200 {
+		t.Errorf("unexpected entry %+v", har.Log.Entries)
+	}
+}
@@ -0,0 +1,168 @@
+package parsers
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"hash"
+	"io"
+
+	"github.com/chainreactors/utils/encode"
+	"github.com/chainreactors/utils/httputils"
+)
+
+// HashSource choose which bytes of Content are hashed
+type HashSource int
+
+const (
+	// HashOrigin hash Origin, the bytes before charset transcoding, comparable with other tools.
+	// Content-Encoding is already decoded, so the same as hashes of a decompressed response
+	// from other http clients, not of the compressed bytes on the wire
+	HashOrigin HashSource = iota
+	// HashDecoded hash the header and the utf-8 transcoded body
+	HashDecoded
+)
+
+// DefaultHashSource is used by Response.Hash
+var DefaultHashSource = HashOrigin
+
+func (r *Response) Hash() {
+	r.HashWith(DefaultHashSource)
+}
+
+// HashWith hashes of Origin computed by ReadContent while reading are reused
+func (r *Response) HashWith(source HashSource) {
+	if source == HashOrigin && r.Content != nil && r.Content.hashes != nil {
+		hashes := *r.Content.hashes
+		r.Hashes = &hashes
+	} else if source == HashOrigin && r.Origin != nil {
+		r.Hashes = NewHashes(r.Origin)
+	} else {
+		r.Hashes = NewHashes(r.Raw)
+	}
+	if r.Resp != nil && r.Resp.TLS != nil {
+		r.Hashes.JA3S = JA3S(r.Resp.TLS)
+	}
+	if r.Cert != nil {
+		r.Hashes.CertSHA256 = r.Cert.SHA256
+	}
+}
+
+// SimhashMaxSize only the leading bytes of body are used by simhash if set, to bound the cost on large body.
+// simhash of body longer than it is changed, so 0 by default, means unlimited
+var SimhashMaxSize = 0
+
+// SimhashStreamSize simhash window of NewHashesWithReader if SimhashMaxSize is 0,
+// only these leading bytes of body are kept in memory, the others are hashed and dropped
+var SimhashStreamSize = 1 << 20
+
+func NewHashes(content []byte) *Hashes {
+	body, header, ok := httputils.SplitHttpRaw(content)
+	if !ok {
+		// not a http raw, hash the content as raw only
+		return &Hashes{
+			BodyMd5:       encode.Md5Hash(nil),
+			HeaderMd5:     encode.Md5Hash(nil),
+			RawMd5:        encode.Md5Hash(content),
+			BodySimhash:   encode.Simhash(nil),
+			HeaderSimhash: encode.Simhash(nil),
+			RawSimhash:    encode.Simhash(simhashPrefix(content)),
+			BodyMmh3:      encode.Mmh3Hash32(nil),
+		}
+	}
+	hs := newHasher(header, 0)
+	hs.Write(body)
+	hs.prefix = simhashPrefix(body)
+	return hs.Hashes()
+}
+
+// NewHashesWithReader hash header and body without materializing the body, raw is header, `\r\n\r\n` and body.
+// simhash only covers the leading SimhashMaxSize bytes, or SimhashStreamSize if unlimited, so it differs from
+// NewHashes on longer body. return the hashes and the bytes of body read
+func NewHashesWithReader(header []byte, body io.Reader) (*Hashes, int64, error) {
+	window := SimhashMaxSize
+	if window <= 0 {
+		window = SimhashStreamSize
+	}
+	hs := newHasher(header, window)
+	n, err := io.Copy(hs, body)
+	if err != nil {
+		return nil, n, err
+	}
+	return hs.Hashes(), n, nil
+}
+
+func simhashPrefix(content []byte) []byte {
+	if SimhashMaxSize > 0 && len(content) > SimhashMaxSize {
+		return content[:SimhashMaxSize]
+	}
+	return content
+}
+
+// hasher hash body incrementally, only the leading window bytes of body are kept for simhash.
+// if window is 0, prefix is not collected and should be set by the caller who holds the body
+type hasher struct {
+	window  int
+	header  []byte
+	bodyMd5 hash.Hash
+	rawMd5  hash.Hash
+	mmh3    *mmh3Writer // encode.Mmh3Hash32 compatible
+	prefix  []byte
+}
+
+func newHasher(header []byte, window int) *hasher {
+	hs := &hasher{
+		window:  window,
+		header:  header,
+		bodyMd5: md5.New(),
+		rawMd5:  md5.New(),
+		mmh3:    newMmh3WriterWithTrailing(true),
+	}
+	hs.rawMd5.Write(header)
+	hs.rawMd5.Write([]byte("\r\n\r\n"))
+	return hs
+}
+
+func (hs *hasher) Write(p []byte) (int, error) {
+	hs.bodyMd5.Write(p)
+	hs.rawMd5.Write(p)
+	hs.mmh3.Write(p)
+	if remain := hs.window - len(hs.prefix); remain >= len(p) {
+		hs.prefix = append(hs.prefix, p...)
+	} else if remain > 0 {
+		hs.prefix = append(hs.prefix, p[:remain]...)
+	}
+	return len(p), nil
+}
+
+// Hashes finish hashing, hasher should not be written after
+func (hs *hasher) Hashes() *Hashes {
+	raw := make([]byte, 0, len(hs.header)+4+len(hs.prefix))
+	raw = append(append(append(raw, hs.header...), "\r\n\r\n"...), hs.prefix...)
+	return &Hashes{
+		BodyMd5:       hex.EncodeToString(hs.bodyMd5.Sum(nil)),
+		HeaderMd5:     encode.Md5Hash(hs.header),
+		RawMd5:        hex.EncodeToString(hs.rawMd5.Sum(nil)),
+		BodySimhash:   encode.Simhash(hs.prefix),
+		HeaderSimhash: encode.Simhash(hs.header),
+		RawSimhash:    encode.Simhash(raw),
+		BodyMmh3:      hs.mmh3.Sum(),
+	}
+}
+
+type Hashes struct {
+	BodyMd5       string `json:"body-md5"`
+	HeaderMd5     string `json:"header-md5"`
+	RawMd5        string `json:"raw-md5"`
+	BodySimhash   string `json:"body-simhash"`
+	HeaderSimhash string `json:"header-simhash"`
+	RawSimhash    string `json:"raw-simhash"`
+	BodyMmh3      string `json:"body-mmh3"`
+	JA3S          string `json:"ja3s,omitempty"`        // tls server hello fingerprint
+	CertSHA256    string `json:"cert-sha256,omitempty"` // sha256 of leaf certificate
+}
+
+var SimhashThreshold uint8 = 8
+
+func (hs *Hashes) Compare(other *Hashes) (uint8, uint8, uint8) {
+	return encode.SimhashCompare(hs.BodySimhash, other.BodySimhash), encode.SimhashCompare(hs.HeaderSimhash, other.HeaderSimhash), encode.SimhashCompare(hs.RawSimhash, other.RawSimhash)
+}
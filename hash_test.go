@@ -0,0 +1,77 @@
+package parsers
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/chainreactors/utils/encode"
+)
+
+func TestNewHashesWithReader(t *testing.T) {
+	header := []byte("HTTP/1.1 200 OK\r\nServer: nginx")
+	body := bytes.Repeat([]byte("hello world "), 1000)
+	raw := append(append(append([]byte{}, header...), "\r\n\r\n"...), body...)
+
+	expect := NewHashes(raw)
+	hs, n, err := NewHashesWithReader(header, bytes.NewReader(body))
+	if err != nil || n != int64(len(body)) {
+		t.Fatalf("unexpected read %d %v", n, err)
+	}
+	if *hs != *expect {
+		t.Errorf("expect the same hashes\n%+v\n%+v", hs, expect)
+	}
+	if hs.BodyMmh3 != encode.Mmh3Hash32(body) {
+		t.Errorf("unexpected mmh3 %s", hs.BodyMmh3)
+	}
+	// body of 57 bytes is encoded into a full line, encode.Mmh3Hash32 appends an extra newline
+	body57 := bytes.Repeat([]byte("a"), 57)
+	if hs := NewHashes(append(append(append([]byte{}, header...), "\r\n\r\n"...), body57...)); hs.BodyMmh3 != encode.Mmh3Hash32(body57) || hs.BodyMmh3 == FaviconHash(body57) {
+		t.Errorf("unexpected mmh3 of full line %s", hs.BodyMmh3)
+	}
+
+	old := SimhashMaxSize
+	SimhashMaxSize = 12
+	defer func() { SimhashMaxSize = old }()
+	if capped := NewHashes(raw); capped.BodySimhash != NewHashes(append(append([]byte{}, raw[:len(header)+4]...), "hello world "...)).BodySimhash {
+		t.Error("expect simhash of the leading bytes")
+	}
+}
+
+// patternReader yield the pattern endlessly without holding more than it in memory
+type patternReader struct {
+	pattern []byte
+	off     int
+}
+
+func (r *patternReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = r.pattern[r.off]
+		r.off = (r.off + 1) % len(r.pattern)
+	}
+	return len(p), nil
+}
+
+func TestNewHashesWithReader_BoundedMemory(t *testing.T) {
+	old := SimhashStreamSize
+	SimhashStreamSize = 64 << 10
+	defer func() { SimhashStreamSize = old }()
+
+	alloc := func(size int64) uint64 {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		hs, n, err := NewHashesWithReader([]byte("HTTP/1.1 200 OK"), io.LimitReader(&patternReader{pattern: []byte("streamed body ")}, size))
+		runtime.ReadMemStats(&after)
+		if err != nil || n != size || hs.BodySimhash == "" {
+			t.Fatalf("unexpected read %d %v", n, err)
+		}
+		return after.TotalAlloc - before.TotalAlloc
+	}
+	// allocation should not grow with body, only the simhash window is kept
+	small, large := alloc(16<<20), alloc(64<<20)
+	if large > small+8<<20 {
+		t.Errorf("expect memory bounded by simhash window, allocated %d bytes for 16MiB body, %d bytes for 64MiB body", small, large)
+	}
+}
@@ -0,0 +1,109 @@
+package parsers
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// SecurityHeaderNames response headers related to browser security policies
+var SecurityHeaderNames = []string{
+	"Strict-Transport-Security",
+	"Content-Security-Policy",
+	"Content-Security-Policy-Report-Only",
+	"X-Frame-Options",
+	"X-Content-Type-Options",
+	"X-XSS-Protection",
+	"Referrer-Policy",
+	"Permissions-Policy",
+	"Cross-Origin-Opener-Policy",
+	"Cross-Origin-Embedder-Policy",
+	"Cross-Origin-Resource-Policy",
+	"Access-Control-Allow-Origin",
+	"Access-Control-Allow-Credentials",
+}
+
+// ParseHeader parse raw http header into http.Header, the status line is skipped, obsolete line folding is supported.
+// keys are canonicalized, so lookup by Get is case-insensitive and values of repeated fields are kept in order
+func ParseHeader(header []byte) http.Header {
+	h := make(http.Header)
+	var lastKey string
+	for i, line := range bytes.Split(header, []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+		if len(line) == 0 {
+			continue
+		}
+		if i == 0 && bytes.HasPrefix(line, []byte("HTTP/")) {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && lastKey != "" {
+			values := h[lastKey]
+			values[len(values)-1] += " " + string(bytes.TrimSpace(line))
+			continue
+		}
+		sep := bytes.IndexByte(line, ':')
+		if sep <= 0 {
+			continue
+		}
+		lastKey = textproto.CanonicalMIMEHeaderKey(string(bytes.TrimSpace(line[:sep])))
+		h[lastKey] = append(h[lastKey], string(bytes.TrimSpace(line[sep+1:])))
+	}
+	return h
+}
+
+// HeaderMap return the header parsed by NewContent, header of live response is used if available.
+// Content not built by NewContent is parsed on every call, nothing is cached to avoid data race
+func (content *Content) HeaderMap() http.Header {
+	if content.headers == nil {
+		return ParseHeader(content.Header)
+	}
+	return content.headers
+}
+
+func (content *Content) GetHeader(key string) string {
+	return content.HeaderMap().Get(key)
+}
+
+func (content *Content) GetHeaderValues(key string) []string {
+	return content.HeaderMap()[textproto.CanonicalMIMEHeaderKey(key)]
+}
+
+// Cookies parse Set-Cookie headers
+func (content *Content) Cookies() []*http.Cookie {
+	return (&http.Response{Header: content.HeaderMap()}).Cookies()
+}
+
+func (content *Content) Location() string {
+	return content.GetHeader("Location")
+}
+
+// ContentType return media type and charset parameter of Content-Type, media type is lowercased
+func (content *Content) ContentType() (string, string) {
+	ct := content.GetHeader("Content-Type")
+	if ct == "" {
+		return "", ""
+	}
+	mediatype, params, err := mime.ParseMediaType(ct)
+	if err != nil {
+		mediatype = strings.ToLower(strings.TrimSpace(strings.SplitN(ct, ";", 2)[0]))
+		if charset, ok := MatchOne(HeaderCharsetRegexp, []byte("Content-Type: "+ct)); ok {
+			return mediatype, NormalizeCharset(charset)
+		}
+		return mediatype, ""
+	}
+	return mediatype, NormalizeCharset(params["charset"])
+}
+
+// SecurityHeaders return the present security headers, keyed by canonical name
+func (content *Content) SecurityHeaders() map[string]string {
+	headers := make(map[string]string)
+	h := content.HeaderMap()
+	for _, name := range SecurityHeaderNames {
+		if values, ok := h[name]; ok {
+			headers[name] = strings.Join(values, ", ")
+		}
+	}
+	return headers
+}
@@ -0,0 +1,86 @@
+package parsers
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestParseHeader(t *testing.T) {
+	h := ParseHeader([]byte("HTTP/1.1 200 OK\r\n" +
+		"content-type: text/html\r\n" +
+		"X-Long: first\r\n" +
+		" second\r\n" +
+		"\tthird\r\n" +
+		"Set-Cookie: a=1\r\n" +
+		"set-cookie: b=2\r\n" +
+		"invalid line\r\n" +
+		": no name\r\n"))
+	if h.Get("Content-Type") != "text/html" {
+		t.Errorf("expect case-insensitive lookup, got %v", h)
+	}
+	if h.Get("X-Long") != "first second third" {
+		t.Errorf("unexpected folded value %q", h.Get("X-Long"))
+	}
+	if cookies := h["Set-Cookie"]; len(cookies) != 2 || cookies[0] != "a=1" || cookies[1] != "b=2" {
+		t.Errorf("unexpected repeated values %v", cookies)
+	}
+	if len(h) != 3 {
+		t.Errorf("status line and invalid lines should be skipped, got %v", h)
+	}
+}
+
+func TestContent_ContentType(t *testing.T) {
+	cases := []struct {
+		header    string
+		mediatype string
+		charset   string
+	}{
+		{"Content-Type: text/html; charset=UTF-8", "text/html", "utf-8"},
+		{"Content-Type: Application/JSON", "application/json", ""},
+		// mime.ParseMediaType fails on the duplicated parameter, charset is matched by regexp
+		{"Content-Type: text/html; charset=gbk; charset=gbk", "text/html", "gbk"},
+		{"Content-Type: text/html;;", "text/html", ""},
+		{"Server: nginx", "", ""},
+	}
+	for _, c := range cases {
+		content := NewContent([]byte("HTTP/1.1 200 OK\r\n" + c.header + "\r\n\r\n"))
+		if mediatype, charset := content.ContentType(); mediatype != c.mediatype || charset != c.charset {
+			t.Errorf("%s: got %q %q", c.header, mediatype, charset)
+		}
+	}
+}
+
+func TestContent_SecurityHeaders(t *testing.T) {
+	content := NewContent([]byte("HTTP/1.1 200 OK\r\n" +
+		"strict-transport-security: max-age=31536000\r\n" +
+		"X-Frame-Options: DENY\r\n" +
+		"X-Frame-Options: SAMEORIGIN\r\n" +
+		"Server: nginx\r\n\r\n"))
+	headers := content.SecurityHeaders()
+	if len(headers) != 2 || headers["Strict-Transport-Security"] != "max-age=31536000" || headers["X-Frame-Options"] != "DENY, SAMEORIGIN" {
+		t.Errorf("unexpected security headers %v", headers)
+	}
+	if content.GetHeader("server") != "nginx" || len(content.GetHeaderValues("x-frame-options")) != 2 {
+		t.Error("unexpected header values")
+	}
+}
+
+func TestContent_HeaderMapConcurrent(t *testing.T) {
+	content := NewContent([]byte("HTTP/1.1 200 OK\r\nServer: nginx\r\nSet-Cookie: a=1\r\n\r\nbody"))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if content.GetHeader("Server") != "nginx" || len(content.Cookies()) != 1 {
+				t.Error("unexpected header")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Content not built by NewContent is parsed on demand
+	if (&Content{Header: []byte("HTTP/1.1 200 OK\r\nServer: iis")}).GetHeader("Server") != "iis" {
+		t.Error("unexpected header of content literal")
+	}
+}
@@ -0,0 +1,56 @@
+package parsers
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	AttributeRegexp = regexp.MustCompile(`([\w:.-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	MetaRegexp      = regexp.MustCompile(`(?is)<meta\b[^>]*>`)
+	LinkRegexp      = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	BaseRegexp      = regexp.MustCompile(`(?is)<base\b[^>]*>`)
+	ScriptRegexp    = regexp.MustCompile(`(?is)<script\b[^>]*>(.*?)</script>`)
+	StyleRegexp     = regexp.MustCompile(`(?is)<style\b[^>]*>.*?</style>`)
+	CommentRegexp   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	TagRegexp       = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// ParseAttributes parse attributes of a start tag, e.g. `<meta name="x" content=y>`, keys are lowercased and values unescaped
+func ParseAttributes(tag string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range AttributeRegexp.FindAllStringSubmatch(tag, -1) {
+		key := strings.ToLower(m[1])
+		if _, ok := attrs[key]; ok {
+			continue
+		}
+		attrs[key] = html.UnescapeString(m[2] + m[3] + m[4])
+	}
+	return attrs
+}
+
+// resolveBase resolve base against `<base href>` of content, return base itself if not declared
+func resolveBase(content string, base *url.URL) *url.URL {
+	if tag := BaseRegexp.FindString(content); tag != "" && base != nil {
+		if href, err := url.Parse(strings.TrimSpace(ParseAttributes(tag)["href"])); err == nil {
+			return base.ResolveReference(href)
+		}
+	}
+	return base
+}
+
+// CleanText unescape html entities and collapse whitespaces
+func CleanText(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// StripTags remove comments, scripts, styles and tags, return the visible text
+func StripTags(content string) string {
+	content = CommentRegexp.ReplaceAllString(content, " ")
+	content = ScriptRegexp.ReplaceAllString(content, " ")
+	content = StyleRegexp.ReplaceAllString(content, " ")
+	content = TagRegexp.ReplaceAllString(content, " ")
+	return CleanText(content)
+}
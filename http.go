@@ -0,0 +1,95 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/chainreactors/utils/httputils"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func NewResponse(resp *http.Response, size int64) *Response {
+	r := &Response{
+		Resp: resp,
+	}
+	r.Content = ReadContent(resp, size)
+
+	if title := MatchTitle(r.Body); title != "" {
+		r.HasTitle = true
+		r.Title = title
+	} else {
+		r.Title = MatchCharacter(r.Body)
+	}
+	r.Server = resp.Header.Get("Server")
+	r.SetTLS(resp.TLS)
+
+	r.History = NewRedirectHops(resp)
+
+	return r
+}
+
+func NewResponseWithRaw(raw []byte) *Response {
+	raw, proto := NormalizeRaw(raw)
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), nil)
+	if err != nil {
+		return nil
+	}
+	setProto(resp, proto)
+
+	return NewResponse(resp, 0)
+}
+
+type Response struct {
+	Server   string         `json:"server"`
+	Title    string         `json:"title"`
+	HasTitle bool           `json:"-"`       // html title: true , body snippet: false
+	History  []*RedirectHop `json:"history"` // redirect chain, the first request first
+	Resp     *http.Response
+	*Content
+	*Hashes `json:"hashes"`
+}
+
+// NewContent keep the bytes before transcoding in Origin, and transcode only the body into utf-8.
+// header is parsed once here, so Content is safe to be read concurrently
+func NewContent(raw []byte) *Content {
+	decoded, charset := DecodeRaw(raw)
+	body, header, _ := httputils.SplitHttpRaw(decoded)
+	return &Content{
+		Body:    body,
+		Header:  header,
+		Raw:     decoded,
+		Origin:  raw,
+		Charset: charset,
+		headers: ParseHeader(header),
+	}
+}
+
+type Content struct {
+	Body    []byte    `json:"-"` // utf-8 body
+	Header  []byte    `json:"-"`
+	Raw     []byte    `json:"raw"` // header and utf-8 body
+	Origin  []byte    `json:"-"`   // bytes before charset transcoding, Content-Encoding already decoded. the same as Raw if not transcoded
+	SSLHost []string  `json:"sslhost"`
+	Cert    *CertInfo `json:"cert,omitempty"`
+	Charset string    `json:"charset,omitempty"`
+	Proto   string    `json:"proto,omitempty"` // HTTP/1.0, HTTP/1.1, HTTP/2 or HTTP/3
+
+	Encoding    string `json:"encoding,omitempty"`     // decoded Content-Encoding
+	EncodedSize int64  `json:"encoded_size,omitempty"` // bytes of body before Content-Encoding decoded
+
+	ContentLength int64         `json:"content_length"`      // declared Content-Length, -1 if unknown
+	ReadLength    int64         `json:"read_length"`         // bytes of decoded body actually read
+	Truncated     bool          `json:"truncated,omitempty"` // body is longer than the size limit
+	ReadTime      time.Duration `json:"read_time"`           // time spent reading body
+
+	headers http.Header
+	hashes  *Hashes // hashes of Origin computed while reading body
+}
+
+func (content *Content) ContentMap() map[string]interface{} {
+	return map[string]interface{}{
+		"content": content.Raw,
+		"cert":    strings.Join(content.SSLHost, ","),
+	}
+}
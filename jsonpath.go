@@ -0,0 +1,231 @@
+package parsers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type jsonPathStepType int
+
+const (
+	jsonKeyStep jsonPathStepType = iota
+	jsonIndexStep
+	jsonWildcardStep
+)
+
+type jsonPathStep struct {
+	typ       jsonPathStepType
+	key       string
+	index     int
+	recursive bool // `..`, the step is applied on all descendants
+}
+
+// JSONPath compiled subset of jsonpath: `$`, `.key`, `['key']`, `[0]`, `[-1]`, `[*]`, `.*` and `..key`.
+// path starts with `/` is treated as json pointer (rfc 6901)
+type JSONPath struct {
+	path    string
+	pointer bool
+	steps   []*jsonPathStep
+}
+
+func CompileJSONPath(path string) (*JSONPath, error) {
+	p := &JSONPath{path: path}
+	if path == "" || strings.HasPrefix(path, "/") {
+		p.pointer = true
+		return p, nil
+	}
+	if !strings.HasPrefix(path, "$") {
+		path = "$." + path
+	}
+	rest := path[1:]
+	for len(rest) > 0 {
+		step := &jsonPathStep{}
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			step.recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			fallthrough
+		case strings.HasPrefix(rest, "."):
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("jsonpath %s: empty key", p.path)
+			}
+			if rest[:end] == "*" {
+				step.typ = jsonWildcardStep
+			} else {
+				step.key = rest[:end]
+			}
+			rest = rest[end:]
+			p.steps = append(p.steps, step)
+			continue
+		}
+
+		if !strings.HasPrefix(rest, "[") {
+			return nil, fmt.Errorf("jsonpath %s: unexpected %q", p.path, rest)
+		}
+		end := strings.IndexByte(rest, ']')
+		if end == -1 {
+			return nil, fmt.Errorf("jsonpath %s: missing ]", p.path)
+		}
+		inner := strings.TrimSpace(rest[1:end])
+		rest = rest[end+1:]
+		switch {
+		case inner == "*":
+			step.typ = jsonWildcardStep
+		case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+			step.key = inner[1 : len(inner)-1]
+		default:
+			i, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath %s: invalid index %q", p.path, inner)
+			}
+			step.typ, step.index = jsonIndexStep, i
+		}
+		p.steps = append(p.steps, step)
+	}
+	return p, nil
+}
+
+func (p *JSONPath) String() string {
+	return p.path
+}
+
+// Find return values selected from decoded json
+func (p *JSONPath) Find(doc interface{}) []interface{} {
+	if p.pointer {
+		if v, ok := JSONPointer(doc, p.path); ok {
+			return []interface{}{v}
+		}
+		return nil
+	}
+	nodes := []interface{}{doc}
+	for _, step := range p.steps {
+		if step.recursive {
+			var all []interface{}
+			for _, node := range nodes {
+				all = appendDescendants(all, node)
+			}
+			nodes = all
+		}
+		var next []interface{}
+		for _, node := range nodes {
+			next = step.apply(next, node)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (step *jsonPathStep) apply(nodes []interface{}, node interface{}) []interface{} {
+	switch step.typ {
+	case jsonKeyStep:
+		if m, ok := node.(map[string]interface{}); ok {
+			if v, ok := m[step.key]; ok {
+				nodes = append(nodes, v)
+			}
+		}
+	case jsonIndexStep:
+		if arr, ok := node.([]interface{}); ok {
+			i := step.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				nodes = append(nodes, arr[i])
+			}
+		}
+	case jsonWildcardStep:
+		nodes = append(nodes, jsonChildren(node)...)
+	}
+	return nodes
+}
+
+// jsonChildren return values of object sorted by key, or elements of array
+func jsonChildren(node interface{}) []interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		children := make([]interface{}, len(keys))
+		for i, k := range keys {
+			children[i] = v[k]
+		}
+		return children
+	case []interface{}:
+		return v
+	default:
+		return nil
+	}
+}
+
+func appendDescendants(nodes []interface{}, node interface{}) []interface{} {
+	nodes = append(nodes, node)
+	for _, child := range jsonChildren(node) {
+		nodes = appendDescendants(nodes, child)
+	}
+	return nodes
+}
+
+// JSONPointer resolve rfc 6901 json pointer, e.g. `/data/items/0/token`
+func JSONPointer(doc interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	node := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		switch v := node.(type) {
+		case map[string]interface{}:
+			child, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			node = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// jsonString string is returned as is, null is ignored, objects and arrays are encoded as compact json
+func jsonString(v interface{}) (string, bool) {
+	switch value := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return value, true
+	case json.Number:
+		return value.String(), true
+	case bool:
+		return strconv.FormatBool(value), true
+	default:
+		bs, err := json.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+		return string(bs), true
+	}
+}
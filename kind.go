@@ -0,0 +1,215 @@
+package parsers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ContentKind classification of response body
+type ContentKind int
+
+const (
+	UnknownKind ContentKind = iota
+	EmptyKind
+	HTMLKind
+	JSONKind
+	XMLKind
+	JavaScriptKind
+	CSSKind
+	TextKind
+	ImageKind
+	ArchiveKind
+	PDFKind
+	BinaryKind
+	ErrorPageKind // default error page of server or framework
+)
+
+func (k ContentKind) Name() string {
+	switch k {
+	case EmptyKind:
+		return "empty"
+	case HTMLKind:
+		return "html"
+	case JSONKind:
+		return "json"
+	case XMLKind:
+		return "xml"
+	case JavaScriptKind:
+		return "js"
+	case CSSKind:
+		return "css"
+	case TextKind:
+		return "text"
+	case ImageKind:
+		return "image"
+	case ArchiveKind:
+		return "archive"
+	case PDFKind:
+		return "pdf"
+	case BinaryKind:
+		return "binary"
+	case ErrorPageKind:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+func (k ContentKind) String() string {
+	return k.Name()
+}
+
+// ParseContentKind return the kind of the name, the inverse of Name
+func ParseContentKind(name string) (ContentKind, error) {
+	for k := UnknownKind; k <= ErrorPageKind; k++ {
+		if k.Name() == name {
+			return k, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown content kind %q", name)
+}
+
+func (k ContentKind) MarshalText() ([]byte, error) {
+	return []byte(k.Name()), nil
+}
+
+func (k *ContentKind) UnmarshalText(text []byte) error {
+	kind, err := ParseContentKind(string(text))
+	if err != nil {
+		return err
+	}
+	*k = kind
+	return nil
+}
+
+// ErrorPage signature of default error page
+type ErrorPage struct {
+	Name   string
+	Regexp *regexp.Regexp
+}
+
+var ErrorPages = []*ErrorPage{
+	{Name: "tomcat", Regexp: regexp.MustCompile(`(?i)<h3>Apache Tomcat(?:/[\d.]+)?</h3>|<title>HTTP Status \d{3}\b[^<]*</title>`)},
+	{Name: "nginx", Regexp: regexp.MustCompile(`(?i)<hr>\s*<center>nginx(?:/[\d.]+)?</center>`)},
+	{Name: "openresty", Regexp: regexp.MustCompile(`(?i)<hr>\s*<center>openresty(?:/[\d.]+)?</center>`)},
+	{Name: "apache", Regexp: regexp.MustCompile(`(?i)<address>Apache(?:/[\d.]+)?[^<]*Server at [^<]*</address>`)},
+	// only error templates, the default welcome page iisstart also mentions iis
+	{Name: "iis", Regexp: regexp.MustCompile(`(?i)<h2>\d{3}(?:\.\d+)? - [^<]+</h2>|<title>IIS [\d.]+ Detailed Error - \d{3}|<h3>HTTP Error \d{3}(?:\.\d+)? - [^<]+</h3>|<div id="header"><h1>Server Error</h1></div>`)},
+	{Name: "asp.net", Regexp: regexp.MustCompile(`(?i)Server Error in '[^']*' Application`)},
+	{Name: "spring-whitelabel", Regexp: regexp.MustCompile(`Whitelabel Error Page`)},
+	{Name: "jetty", Regexp: regexp.MustCompile(`(?i)Powered by Jetty://`)},
+	{Name: "weblogic", Regexp: regexp.MustCompile(`Error \d{3}--[\w ]+</H4>|From RFC 2068 <i>Hypertext Transfer Protocol`)},
+	{Name: "django", Regexp: regexp.MustCompile(`You're seeing this error because you have <code>DEBUG = True</code>`)},
+	{Name: "laravel", Regexp: regexp.MustCompile(`Whoops, looks like something went wrong\.`)},
+	{Name: "express", Regexp: regexp.MustCompile(`<pre>Cannot (?:GET|POST|PUT|DELETE|PATCH|HEAD|OPTIONS) [^<]*</pre>`)},
+	{Name: "werkzeug", Regexp: regexp.MustCompile(`The requested URL was not found on the server\. If you entered the URL manually`)},
+	{Name: "thinkphp", Regexp: regexp.MustCompile(`<title>系统发生错误</title>|ThinkPHP V[\d.]+ \{ Fast & Simple OOP PHP Framework \}`)},
+}
+
+var (
+	JavaScriptRegexp = regexp.MustCompile(`^(?:"use strict"|'use strict'|!function|\(function|\(\(\)|function\s*[\w$]*\s*\(|(?:var|let|const)\s+[\w$]+\s*=|import\s|export\s|window\.|document\.|define\(|webpackJsonp|\(self\.webpackChunk)`)
+	CSSRegexp        = regexp.MustCompile(`^(?:@charset|@import|@media|@font-face|:root\s*\{|(?:[.#]?[\w-]+\s*,?\s*)+\{\s*[\w-]+\s*:)`)
+)
+
+// MatchErrorPage return the name of matched default error page, empty if not matched
+func (content *Content) MatchErrorPage() string {
+	if len(content.Body) == 0 {
+		return ""
+	}
+	for _, page := range ErrorPages {
+		if page.Regexp.Match(content.Body) {
+			return page.Name
+		}
+	}
+	return ""
+}
+
+// Kind classify body by magic bytes, declared Content-Type and content, default error pages take priority
+func (content *Content) Kind() ContentKind {
+	body := content.Body
+	if len(bytes.TrimSpace(body)) == 0 {
+		return EmptyKind
+	}
+	if kind := sniffBinaryKind(body); kind != UnknownKind {
+		return kind
+	}
+	if content.MatchErrorPage() != "" {
+		return ErrorPageKind
+	}
+
+	mediatype, _ := content.ContentType()
+	trimmed := bytes.TrimSpace(body)
+	switch {
+	case strings.Contains(mediatype, "json"):
+		if json.Valid(trimmed) {
+			return JSONKind
+		}
+	case strings.Contains(mediatype, "javascript") || strings.Contains(mediatype, "ecmascript"):
+		return JavaScriptKind
+	case mediatype == "text/css":
+		return CSSKind
+	}
+
+	if (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		return JSONKind
+	}
+	sniffed := http.DetectContentType(body)
+	switch {
+	case strings.HasPrefix(sniffed, "text/html"):
+		return HTMLKind
+	case strings.HasPrefix(sniffed, "text/xml"):
+		head := trimmed
+		if len(head) > 512 {
+			head = head[:512]
+		}
+		if bytes.Contains(bytes.ToLower(head), []byte("<svg")) {
+			return ImageKind
+		}
+		return XMLKind
+	case strings.HasPrefix(sniffed, "image/"):
+		return ImageKind
+	case strings.HasPrefix(sniffed, "text/"):
+		if bytes.HasPrefix(trimmed, []byte("<svg")) {
+			return ImageKind
+		}
+		if mediatype == "text/html" || mediatype == "application/xhtml+xml" {
+			return HTMLKind
+		}
+		if strings.HasSuffix(mediatype, "xml") && trimmed[0] == '<' {
+			return XMLKind
+		}
+		if JavaScriptRegexp.Match(trimmed) {
+			return JavaScriptKind
+		}
+		if CSSRegexp.Match(trimmed) {
+			return CSSKind
+		}
+		return TextKind
+	default:
+		return BinaryKind
+	}
+}
+
+// sniffBinaryKind match magic bytes of image, archive and pdf
+func sniffBinaryKind(body []byte) ContentKind {
+	switch {
+	case bytes.HasPrefix(body, []byte("%PDF-")):
+		return PDFKind
+	case bytes.HasPrefix(body, []byte("PK\x03\x04")), bytes.HasPrefix(body, []byte("PK\x05\x06")),
+		bytes.HasPrefix(body, []byte("\x1f\x8b")), bytes.HasPrefix(body, []byte("Rar!\x1a\x07")),
+		bytes.HasPrefix(body, []byte("7z\xbc\xaf\x27\x1c")), bytes.HasPrefix(body, []byte("BZh")),
+		bytes.HasPrefix(body, []byte("\xfd7zXZ\x00")), len(body) > 262 && bytes.Equal(body[257:262], []byte("ustar")):
+		return ArchiveKind
+	case bytes.HasPrefix(body, []byte("\x00\x00\x01\x00")):
+		// ico, not sniffed by http.DetectContentType
+		return ImageKind
+	}
+	if strings.HasPrefix(http.DetectContentType(body), "image/") {
+		return ImageKind
+	}
+	return UnknownKind
+}
@@ -0,0 +1,49 @@
+package parsers
+
+import (
+	"testing"
+)
+
+func TestContent_Kind(t *testing.T) {
+	cases := map[string]ContentKind{
+		"HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<!DOCTYPE html><html><body>hi</body></html>":                            HTMLKind,
+		"HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n{\"code\": 0, \"data\": []}":                                           JSONKind,
+		"HTTP/1.1 200 OK\r\nContent-Type: application/xml\r\n\r\n<?xml version=\"1.0\"?><root/>":                                   XMLKind,
+		"HTTP/1.1 200 OK\r\nContent-Type: application/javascript\r\n\r\nvar a = 1;":                                                JavaScriptKind,
+		"HTTP/1.1 200 OK\r\n\r\n!function(e){console.log(e)}(1);":                                                                  JavaScriptKind,
+		"HTTP/1.1 200 OK\r\nContent-Type: text/css\r\n\r\nbody { color: red }":                                                     CSSKind,
+		"HTTP/1.1 200 OK\r\n\r\n\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR":                                                               ImageKind,
+		"HTTP/1.1 200 OK\r\n\r\n<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>":                                                  ImageKind,
+		"HTTP/1.1 200 OK\r\n\r\nPK\x03\x04\x14\x00\x00\x00":                                                                        ArchiveKind,
+		"HTTP/1.1 200 OK\r\n\r\n%PDF-1.4\n":                                                                                        PDFKind,
+		"HTTP/1.1 200 OK\r\n\r\n\x00\x01\x02\x03\xff\xfe":                                                                          BinaryKind,
+		"HTTP/1.1 200 OK\r\n\r\nplain text":                                                                                        TextKind,
+		"HTTP/1.1 204 No Content\r\n\r\n":                                                                                          EmptyKind,
+		"HTTP/1.1 404 Not Found\r\nContent-Type: text/html\r\n\r\n<html><body><hr><center>nginx/1.18.0</center></body></html>":     ErrorPageKind,
+		"HTTP/1.1 500 OK\r\nContent-Type: text/html\r\n\r\n<html><body><h1>Whitelabel Error Page</h1></body></html>":               ErrorPageKind,
+		"HTTP/1.1 404 OK\r\nContent-Type: text/html\r\n\r\n<title>HTTP Status 404 – Not Found</title><h3>Apache Tomcat/9.0.1</h3>": ErrorPageKind,
+	}
+	for raw, expect := range cases {
+		if kind := NewContent([]byte(raw)).Kind(); kind != expect {
+			t.Errorf("expect %s, got %s for %q", expect, kind, raw)
+		}
+	}
+
+	pages := map[string]string{
+		"<pre>Cannot GET /x</pre>": "express",
+		`<div id="header"><h1>Server Error</h1></div><fieldset><h2>404 - File or directory not found.</h2>`: "iis",
+		"<title>IIS 10.0 Detailed Error - 404.0 - Not Found</title>":                                        "iis",
+		"<h3>HTTP Error 500.19 - Internal Server Error</h3>":                                                "iis",
+		// default welcome page is not an error page
+		`<title>IIS Windows Server</title><a href="http://go.microsoft.com/fwlink/?linkid=66138&amp;clcid=0x409"><img src="iisstart.png" alt="IIS"></a>`: "",
+		"<title>IIS7</title><p>Internet Information Services</p>": "",
+	}
+	for body, expect := range pages {
+		if page := NewContent([]byte("HTTP/1.1 200 OK\r\n\r\n" + body)).MatchErrorPage(); page != expect {
+			t.Errorf("expect %q, got %q for %q", expect, page, body)
+		}
+	}
+	if kind, err := ParseContentKind("js"); err != nil || kind != JavaScriptKind {
+		t.Errorf("unexpected kind %s %v", kind, err)
+	}
+}
@@ -0,0 +1,161 @@
+package parsers
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/chainreactors/fingers/common"
+	"github.com/chainreactors/utils/httputils"
+)
+
+// SessionCookie infer backend framework and language from session cookie name
+type SessionCookie struct {
+	Name      string         // exact cookie name
+	Prefix    bool           // match Name as prefix
+	Regexp    *regexp.Regexp // match cookie name by regexp, used if not nil
+	Framework string
+	Language  string
+}
+
+func (sc *SessionCookie) Match(name string) bool {
+	if sc.Regexp != nil {
+		return sc.Regexp.MatchString(name)
+	} else if sc.Prefix {
+		return strings.HasPrefix(name, sc.Name)
+	}
+	return name == sc.Name
+}
+
+var SessionCookies = []*SessionCookie{
+	{Name: "JSESSIONID", Language: "java"},
+	{Name: "JSESSIONIDSSO", Language: "java"},
+	{Name: "rememberMe", Framework: "shiro", Language: "java"},
+	{Name: "PLAY_SESSION", Framework: "play", Language: "java"},
+	{Name: "PHPSESSID", Language: "php"},
+	{Name: "laravel_session", Framework: "laravel", Language: "php"},
+	{Name: "ci_session", Framework: "codeigniter", Language: "php"},
+	{Name: "CAKEPHP", Framework: "cakephp", Language: "php"},
+	{Name: "symfony", Framework: "symfony", Language: "php"},
+	{Name: "YII_CSRF_TOKEN", Framework: "yii", Language: "php"},
+	{Name: "wordpress_", Prefix: true, Framework: "wordpress", Language: "php"},
+	{Name: "wp-settings-", Prefix: true, Framework: "wordpress", Language: "php"},
+	{Regexp: regexp.MustCompile(`^S?SESS[0-9a-f]{32}$`), Framework: "drupal", Language: "php"},
+	{Name: "ASP.NET_SessionId", Language: "asp.net"},
+	{Name: ".ASPXAUTH", Language: "asp.net"},
+	{Name: ".AspNetCore.", Prefix: true, Framework: "asp.net core", Language: "asp.net"},
+	{Name: "ASPSESSIONID", Prefix: true, Language: "asp"},
+	{Name: "connect.sid", Framework: "express", Language: "nodejs"},
+	{Name: "express.sid", Framework: "express", Language: "nodejs"},
+	{Name: "express:sess", Framework: "express", Language: "nodejs"},
+	{Name: "koa:sess", Framework: "koa", Language: "nodejs"},
+	{Name: "koa.sess", Framework: "koa", Language: "nodejs"},
+	{Name: "sails.sid", Framework: "sails", Language: "nodejs"},
+	{Name: "django_language", Framework: "django", Language: "python"},
+	{Name: "_session_id", Framework: "rails", Language: "ruby"},
+	{Name: "rack.session", Framework: "rack", Language: "ruby"},
+	{Name: "_gitlab_session", Framework: "gitlab", Language: "ruby"},
+	{Name: "beegosessionID", Framework: "beego", Language: "go"},
+	{Name: "grafana_session", Framework: "grafana", Language: "go"},
+	{Name: "mojolicious", Framework: "mojolicious", Language: "perl"},
+	{Name: "CFID", Framework: "coldfusion", Language: "cfml"},
+	{Name: "CFTOKEN", Framework: "coldfusion", Language: "cfml"},
+	{Name: "BIGipServer", Prefix: true, Framework: "f5-big-ip"},
+}
+
+// PoweredByAliases map lowercased X-Powered-By product to framework and language
+var PoweredByAliases = map[string][2]string{
+	"php":               {"", "php"},
+	"asp.net":           {"", "asp.net"},
+	"servlet":           {"", "java"},
+	"jsp":               {"", "java"},
+	"express":           {"express", "nodejs"},
+	"next.js":           {"next.js", "nodejs"},
+	"nuxt.js":           {"nuxt.js", "nodejs"},
+	"sails":             {"sails", "nodejs"},
+	"koa":               {"koa", "nodejs"},
+	"thinkphp":          {"thinkphp", "php"},
+	"phusion passenger": {"phusion passenger", "ruby"},
+}
+
+// MatchLanguages infer backend languages and frameworks from X-Powered-By, X-AspNet-Version and session cookies
+func (content *Content) MatchLanguages() common.Frameworks {
+	frames := make(common.Frameworks)
+	h := content.HeaderMap()
+
+	for _, powered := range h["X-Powered-By"] {
+		for _, token := range strings.FieldsFunc(powered, func(r rune) bool { return r == ',' || r == ';' }) {
+			name, version := splitProduct(token)
+			if name == "" {
+				continue
+			}
+			if alias, ok := PoweredByAliases[name]; ok {
+				if alias[0] != "" {
+					frames.Add(common.NewFrameworkWithVersion(alias[0], common.FrameFromDefault, version))
+					frames.Add(common.NewFramework(alias[1], common.FrameFromDefault))
+				} else {
+					frames.Add(common.NewFrameworkWithVersion(alias[1], common.FrameFromDefault, version))
+				}
+			} else {
+				frames.Add(common.NewFrameworkWithVersion(name, common.FrameFromDefault, version))
+			}
+		}
+	}
+	if v := h.Get("X-AspNet-Version"); v != "" {
+		frames.Add(common.NewFrameworkWithVersion("asp.net", common.FrameFromDefault, v))
+	}
+	if v := h.Get("X-AspNetMvc-Version"); v != "" {
+		frames.Add(common.NewFrameworkWithVersion("asp.net mvc", common.FrameFromDefault, v))
+	}
+
+	for _, cookie := range content.Cookies() {
+		for _, sc := range SessionCookies {
+			if !sc.Match(cookie.Name) {
+				continue
+			}
+			if sc.Framework != "" {
+				frames.Add(common.NewFramework(sc.Framework, common.FrameFromDefault))
+			}
+			if sc.Language != "" {
+				frames.Add(common.NewFramework(sc.Language, common.FrameFromDefault))
+			}
+		}
+	}
+	return frames
+}
+
+// splitProduct split `PHP/7.4.3` into `php` and `7.4.3`
+func splitProduct(token string) (string, string) {
+	token = strings.TrimSpace(token)
+	if i := strings.IndexByte(token, '/'); i != -1 {
+		return strings.ToLower(strings.TrimSpace(token[:i])), strings.TrimSpace(token[i+1:])
+	}
+	return strings.ToLower(token), ""
+}
+
+// MatchLanguage return X-Powered-By if present, otherwise the language inferred from session cookie
+func MatchLanguage(resp *http.Response) string {
+	return matchLanguage(&Content{headers: resp.Header})
+}
+
+func MatchLanguageWithRaw(content []byte) string {
+	_, header, ok := httputils.SplitHttpRaw(content)
+	if !ok {
+		header = content
+	}
+	return matchLanguage(&Content{Header: header})
+}
+
+func matchLanguage(content *Content) string {
+	if powered := content.GetHeader("X-Powered-By"); powered != "" {
+		return powered
+	}
+	for _, cookie := range content.Cookies() {
+		for _, sc := range SessionCookies {
+			if sc.Language != "" && sc.Match(cookie.Name) {
+				return sc.Language
+			}
+		}
+	}
+	return ""
+}
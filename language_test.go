@@ -0,0 +1,75 @@
+package parsers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/chainreactors/fingers/common"
+)
+
+// frameNames return sorted `name` or `name/version` of frameworks
+func frameNames(frames common.Frameworks) string {
+	var names []string
+	for name, frame := range frames {
+		if frame.Version != "" {
+			name += "/" + frame.Version
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func TestContent_MatchLanguages(t *testing.T) {
+	cases := []struct {
+		header string
+		expect string
+	}{
+		{"X-Powered-By: PHP/7.4.3", "php/7.4.3"},
+		{"X-Powered-By: Express", "express,nodejs"},
+		{"X-Powered-By: PHP/8.1, ThinkPHP", "php/8.1,thinkphp"},
+		{"X-Powered-By: ASP.NET\r\nX-AspNet-Version: 4.0.30319\r\nX-AspNetMvc-Version: 5.2", "asp.net mvc/5.2,asp.net/4.0.30319"},
+		{"X-Powered-By: Servlet/3.0; JSP/2.2", "java/3.0"},
+		{"X-Powered-By: Unknown-Stack/1.0", "unknown-stack/1.0"},
+		{"Set-Cookie: JSESSIONID=1; Path=/\r\nSet-Cookie: rememberMe=deleteMe", "java,shiro"},
+		// prefix rules
+		{"Set-Cookie: wordpress_logged_in_abc=1", "php,wordpress"},
+		{"Set-Cookie: BIGipServerpool_web=123", "f5-big-ip"},
+		// regexp rule
+		{"Set-Cookie: SESS0123456789abcdef0123456789abcdef=1", "drupal,php"},
+		{"Set-Cookie: SESSxyz=1", ""},
+		{"Set-Cookie: csrftoken=abc", ""},
+		{"Server: nginx", ""},
+	}
+	for _, c := range cases {
+		content := NewContent([]byte("HTTP/1.1 200 OK\r\n" + c.header + "\r\n\r\n"))
+		if got := frameNames(content.MatchLanguages()); got != c.expect {
+			t.Errorf("%q: got %q, want %q", c.header, got, c.expect)
+		}
+	}
+}
+
+func TestMatchLanguage(t *testing.T) {
+	cases := []struct {
+		raw    string
+		expect string
+	}{
+		{"HTTP/1.1 200 OK\r\nX-Powered-By: PHP/7.4.3\r\nSet-Cookie: JSESSIONID=1\r\n\r\nbody", "PHP/7.4.3"},
+		{"HTTP/1.1 200 OK\r\nSet-Cookie: laravel_session=1\r\n\r\n", "php"},
+		{"HTTP/1.1 200 OK\r\nSet-Cookie: BIGipServerpool=1\r\n\r\n", ""},
+		// header only, without body separator
+		{"HTTP/1.1 200 OK\r\nSet-Cookie: ASPSESSIONIDQQ=1", "asp"},
+	}
+	for _, c := range cases {
+		if got := MatchLanguageWithRaw([]byte(c.raw)); got != c.expect {
+			t.Errorf("raw %q: got %q, want %q", c.raw, got, c.expect)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Set-Cookie": {"connect.sid=1"}}}
+	if got := MatchLanguage(resp); got != "nodejs" {
+		t.Errorf("unexpected language %q", got)
+	}
+}
@@ -0,0 +1,153 @@
+package parsers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	SeverityInfo   = "info"
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+var (
+	VersionRegexp = regexp.MustCompile(`\d+(\.\d+)+`)
+	MaxAgeRegexp  = regexp.MustCompile(`(?i)max-age\s*=\s*"?(\d+)`)
+)
+
+// HSTSMinMaxAge hsts max-age less than 180 days is reported as weak
+var HSTSMinMaxAge = 15552000
+
+type Finding struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+func NewFinding(name, severity, detail string) *Finding {
+	return &Finding{Name: name, Severity: severity, Detail: detail}
+}
+
+func (f *Finding) String() string {
+	if f.Detail != "" {
+		return fmt.Sprintf("%s:%s", f.Name, f.Detail)
+	}
+	return f.Name
+}
+
+type Findings []*Finding
+
+func (fs Findings) String() string {
+	var s strings.Builder
+	for _, f := range fs {
+		s.WriteString("[ " + f.String() + " ]")
+	}
+	if s.Len() > 0 {
+		s.WriteString(" ")
+	}
+	return s.String()
+}
+
+func (fs *Findings) Merge(other Findings) {
+	*fs = append(*fs, other...)
+}
+
+func (fs Findings) Has(name string) bool {
+	for _, f := range fs {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTLS return true if response is received over tls, or requested with https
+func (r *Response) IsTLS() bool {
+	if r.Resp == nil {
+		return false
+	}
+	return r.Resp.TLS != nil || (r.Resp.Request != nil && r.Resp.Request.URL != nil && r.Resp.Request.URL.Scheme == "https")
+}
+
+// Posture analyze security headers, cookie flags, cors, version disclosure and certificate of response
+func (r *Response) Posture() Findings {
+	var findings Findings
+	h := r.HeaderMap()
+	isTLS := r.IsTLS()
+	mediatype, _ := r.ContentType()
+	isHTML := mediatype == "text/html" || mediatype == "application/xhtml+xml"
+
+	if isTLS {
+		if sts := h.Get("Strict-Transport-Security"); sts == "" {
+			findings = append(findings, NewFinding("missing-hsts", SeverityLow, ""))
+		} else if m := MaxAgeRegexp.FindStringSubmatch(sts); m == nil {
+			findings = append(findings, NewFinding("weak-hsts", SeverityInfo, sts))
+		} else if age, _ := strconv.Atoi(m[1]); age < HSTSMinMaxAge {
+			findings = append(findings, NewFinding("weak-hsts", SeverityInfo, sts))
+		}
+	}
+
+	if isHTML {
+		csp := h.Get("Content-Security-Policy")
+		if csp == "" {
+			findings = append(findings, NewFinding("missing-csp", SeverityInfo, ""))
+		}
+		if h.Get("X-Frame-Options") == "" && !strings.Contains(strings.ToLower(csp), "frame-ancestors") {
+			findings = append(findings, NewFinding("missing-x-frame-options", SeverityLow, ""))
+		}
+		if h.Get("X-Content-Type-Options") == "" {
+			findings = append(findings, NewFinding("missing-x-content-type-options", SeverityInfo, ""))
+		}
+	}
+
+	for _, c := range r.Cookies() {
+		var issues []string
+		if !c.HttpOnly {
+			issues = append(issues, "HttpOnly")
+		}
+		if !c.Secure && isTLS {
+			issues = append(issues, "Secure")
+		}
+		if len(issues) > 0 {
+			findings = append(findings, NewFinding("insecure-cookie", SeverityLow, c.Name+" missing "+strings.Join(issues, ",")))
+		}
+		if c.SameSite == http.SameSiteNoneMode && !c.Secure {
+			findings = append(findings, NewFinding("insecure-cookie", SeverityLow, c.Name+" SameSite=None without Secure"))
+		}
+	}
+
+	if origin := h.Get("Access-Control-Allow-Origin"); origin != "" {
+		credentials := strings.EqualFold(h.Get("Access-Control-Allow-Credentials"), "true")
+		var reflected bool
+		if r.Resp != nil && r.Resp.Request != nil && r.Resp.Request.Header != nil {
+			reflected = origin == r.Resp.Request.Header.Get("Origin")
+		}
+		switch {
+		case reflected && credentials:
+			findings = append(findings, NewFinding("permissive-cors", SeverityHigh, origin+" with credentials"))
+		case origin == "null":
+			findings = append(findings, NewFinding("permissive-cors", SeverityMedium, origin))
+		case origin == "*" || reflected:
+			// browsers reject wildcard with credentials, so the credentials make no difference
+			findings = append(findings, NewFinding("permissive-cors", SeverityLow, origin))
+		}
+	}
+
+	if server := h.Get("Server"); VersionRegexp.MatchString(server) {
+		findings = append(findings, NewFinding("server-version-disclosure", SeverityInfo, server))
+	}
+	for _, name := range []string{"X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version"} {
+		if v := h.Get(name); v != "" {
+			findings = append(findings, NewFinding(strings.ToLower(name), SeverityInfo, v))
+		}
+	}
+	if r.Cert != nil {
+		findings.Merge(r.Cert.Findings())
+	}
+	return findings
+}
@@ -0,0 +1,49 @@
+package parsers
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestResponse_Posture(t *testing.T) {
+	resp := NewResponseWithRaw([]byte("HTTP/1.1 200 OK\r\n" +
+		"Content-Type: text/html\r\n" +
+		"Server: nginx/1.18.0\r\n" +
+		"X-Powered-By: PHP/7.4.3\r\n" +
+		"Access-Control-Allow-Origin: https://evil.com\r\n" +
+		"Access-Control-Allow-Credentials: true\r\n" +
+		"Set-Cookie: PHPSESSID=abc; path=/\r\n" +
+		"Set-Cookie: safe=1; Secure; HttpOnly\r\n\r\n<html></html>"))
+	resp.Resp.Request = &http.Request{URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/"}, Header: http.Header{"Origin": {"https://evil.com"}}}
+
+	findings := resp.Posture()
+	for _, name := range []string{"missing-hsts", "missing-csp", "missing-x-frame-options", "insecure-cookie", "permissive-cors", "server-version-disclosure", "x-powered-by"} {
+		if !findings.Has(name) {
+			t.Errorf("expect finding %s, got %s", name, findings.String())
+		}
+	}
+	for _, f := range findings {
+		if f.Name == "insecure-cookie" && f.Detail != "PHPSESSID missing HttpOnly,Secure" {
+			t.Errorf("unexpected cookie finding %s", f)
+		}
+		if f.Name == "permissive-cors" && f.Severity != SeverityHigh {
+			t.Errorf("unexpected cors finding severity %s", f.Severity)
+		}
+	}
+
+	wildcard := NewResponseWithRaw([]byte("HTTP/1.1 200 OK\r\nAccess-Control-Allow-Origin: *\r\nAccess-Control-Allow-Credentials: true\r\n\r\n"))
+	if !wildcard.Posture().Has("permissive-cors") {
+		t.Error("expect permissive-cors of wildcard")
+	}
+	for _, f := range wildcard.Posture() {
+		if f.Name == "permissive-cors" && f.Severity != SeverityLow {
+			t.Errorf("wildcard with credentials is rejected by browsers, got %s", f)
+		}
+	}
+
+	hardened := NewResponseWithRaw([]byte("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nServer: nginx\r\n\r\n{}"))
+	if findings := hardened.Posture(); len(findings) != 0 {
+		t.Errorf("expect no findings, got %s", findings.String())
+	}
+}
@@ -0,0 +1,282 @@
+package parsers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ProtoHTTP10 = "HTTP/1.0"
+	ProtoHTTP11 = "HTTP/1.1"
+	ProtoHTTP2  = "HTTP/2"
+	ProtoHTTP3  = "HTTP/3"
+)
+
+var (
+	StatusLineRegexp = regexp.MustCompile(`^(HTTP/[\d.]+)\s+(\d{3})(?:\s+(.*))?$`)
+	AltSvcRegexp     = regexp.MustCompile(`([\w.-]+)\s*=\s*"([^"]*)"((?:\s*;\s*[\w-]+\s*=\s*"?[^",;]*"?)*)`)
+	AltSvcMaRegexp   = regexp.MustCompile(`(?i);\s*ma\s*=\s*"?(\d+)`)
+)
+
+// NormalizeProto return HTTP/1.0, HTTP/1.1, HTTP/2 or HTTP/3, e.g. `HTTP/2.0` to `HTTP/2`, `h3` to `HTTP/3`
+func NormalizeProto(proto string) string {
+	switch strings.ToUpper(strings.TrimSpace(proto)) {
+	case "HTTP/1.0":
+		return ProtoHTTP10
+	case "HTTP/1.1", "HTTP/1", "":
+		return ProtoHTTP11
+	case "HTTP/2", "HTTP/2.0", "H2", "H2C":
+		return ProtoHTTP2
+	case "HTTP/3", "HTTP/3.0", "H3":
+		return ProtoHTTP3
+	default:
+		return proto
+	}
+}
+
+// statusLine build status line in text framing. http/2 and http/3 have no status line,
+// so HTTP/1.1 is used to keep the raw of the same response identical across protocols
+func statusLine(proto string, code int, status string) string {
+	if proto = NormalizeProto(proto); proto != ProtoHTTP10 {
+		proto = ProtoHTTP11
+	}
+	reason := strings.TrimSpace(strings.TrimPrefix(status, strconv.Itoa(code)))
+	if reason == "" {
+		reason = http.StatusText(code)
+	}
+	return proto + " " + strconv.Itoa(code) + " " + reason
+}
+
+// ReadHeader write headers sorted by name, all values of repeated headers are kept
+func ReadHeader(resp *http.Response) []byte {
+	keys := make([]string, 0, len(resp.Header))
+	for k := range resp.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var header bytes.Buffer
+	for _, k := range keys {
+		for _, v := range resp.Header[k] {
+			header.WriteString(k + ": " + v + "\r\n")
+		}
+	}
+	return header.Bytes()
+}
+
+// ReadRaw read response into canonical raw form, see statusLine and ReadHeader
+func ReadRaw(resp *http.Response) []byte {
+	return readRaw(resp, ReadBody(resp))
+}
+
+func ReadRawWithSize(resp *http.Response, size int64) []byte {
+	return readRaw(resp, ReadBodyWithSize(resp, size))
+}
+
+// ReadContent decode Content-Encoding and read response into Content, at most size bytes of body are read if size > 0.
+// the declared length, bytes read, truncation and time spent are recorded.
+// hashes of Origin are computed while the body is read, see Response.HashWith
+func ReadContent(resp *http.Response, size int64) *Content {
+	contentLength := resp.ContentLength
+	encoding, encoded := decodeContentEncoding(resp)
+	head := statusLine(resp.Proto, resp.StatusCode, resp.Status) + "\r\n" + string(ReadHeader(resp))
+	// the same header as httputils.SplitHttpRaw returns, the last CRLF belongs to the separator
+	hs := newHasher([]byte(strings.TrimSuffix(head, "\r\n")), 0)
+
+	start := time.Now()
+	var body []byte
+	var truncated bool
+	if size > 0 {
+		body, truncated = readBodyWithLimit(resp, size, hs)
+	} else {
+		body = readBody(resp, hs)
+	}
+	readTime := time.Since(start)
+	hs.prefix = simhashPrefix(body)
+
+	raw := make([]byte, 0, len(head)+2+len(body))
+	raw = append(append(append(raw, head...), "\r\n"...), body...)
+	content := NewContent(raw)
+	content.headers = resp.Header
+	content.hashes = hs.Hashes()
+	content.Proto = NormalizeProto(resp.Proto)
+	content.ContentLength = contentLength
+	content.ReadLength = int64(len(body))
+	content.Truncated = truncated
+	content.ReadTime = readTime
+	if encoded != nil {
+		content.Encoding = encoding
+		content.EncodedSize = encoded.n
+	}
+	return content
+}
+
+func readRaw(resp *http.Response, body []byte) []byte {
+	var raw bytes.Buffer
+	raw.WriteString(statusLine(resp.Proto, resp.StatusCode, resp.Status) + "\r\n")
+	raw.Write(ReadHeader(resp))
+	raw.WriteString("\r\n")
+	raw.Write(body)
+	return raw.Bytes()
+}
+
+func ReadBody(resp *http.Response) []byte {
+	return readBody(resp, nil)
+}
+
+func ReadBodyWithSize(resp *http.Response, size int64) []byte {
+	body, _ := ReadBodyWithLimit(resp, size)
+	return body
+}
+
+// ReadBodyWithLimit read at most size bytes of body, return true if body is longer than size.
+// one more byte is read to detect truncation, body is not closed for the remaining data
+func ReadBodyWithLimit(resp *http.Response, size int64) ([]byte, bool) {
+	return readBodyWithLimit(resp, size, nil)
+}
+
+// readBody read the whole body and close it, bytes of body are also written into w if not nil
+func readBody(resp *http.Response, w io.Writer) []byte {
+	if resp.Body == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	_, _ = io.Copy(teeWriter(&buf, w), resp.Body)
+	_ = resp.Body.Close()
+	return buf.Bytes()
+}
+
+// readBodyWithLimit see ReadBodyWithLimit, the byte read for truncation detection is not written into w
+func readBodyWithLimit(resp *http.Response, size int64, w io.Writer) ([]byte, bool) {
+	if resp.Body == nil {
+		return nil, false
+	}
+	var buf bytes.Buffer
+	if size > 0 {
+		_, _ = io.CopyN(teeWriter(&buf, w), resp.Body, size)
+	}
+	var one [1]byte
+	n, _ := io.ReadFull(resp.Body, one[:])
+	return buf.Bytes(), n > 0
+}
+
+func teeWriter(buf *bytes.Buffer, w io.Writer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return io.MultiWriter(buf, w)
+}
+
+// NormalizeRaw convert raw response dumped from http/2 or http/3, e.g. `HTTP/2 200` status line of curl,
+// or `:status: 200` pseudo headers, into HTTP/1.1 text framing that http.ReadResponse accepts.
+// header names are kept, pseudo headers are removed. return the normalized raw and the original protocol
+func NormalizeRaw(raw []byte) ([]byte, string) {
+	sep, sepLen := bytes.Index(raw, []byte("\r\n\r\n")), 4
+	if lf := bytes.Index(raw, []byte("\n\n")); lf != -1 && (sep == -1 || lf < sep) {
+		sep, sepLen = lf, 2
+	}
+	head, body := raw, []byte(nil)
+	if sep != -1 {
+		head, body = raw[:sep], raw[sep+sepLen:]
+	}
+
+	lines := strings.Split(strings.Replace(string(head), "\r\n", "\n", -1), "\n")
+	proto, code, reason := "", 0, ""
+	if m := StatusLineRegexp.FindStringSubmatch(strings.TrimSpace(lines[0])); m != nil {
+		proto, reason = NormalizeProto(m[1]), m[3]
+		code, _ = strconv.Atoi(m[2])
+		lines = lines[1:]
+	}
+
+	var headers []string
+	for _, line := range lines {
+		if !strings.HasPrefix(line, ":") {
+			headers = append(headers, line)
+			continue
+		}
+		// pseudo header, `:status: 200`
+		kv := strings.SplitN(line[1:], ":", 2)
+		if len(kv) == 2 && strings.EqualFold(strings.TrimSpace(kv[0]), "status") {
+			code, _ = strconv.Atoi(strings.TrimSpace(kv[1]))
+			if proto == "" {
+				proto = ProtoHTTP2
+			}
+		}
+	}
+	if code == 0 || (proto != ProtoHTTP2 && proto != ProtoHTTP3) {
+		return raw, proto
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(statusLine(proto, code, reason) + "\r\n")
+	for _, line := range headers {
+		buf.WriteString(line + "\r\n")
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes(), proto
+}
+
+// setProto set protocol version of response parsed from normalized raw
+func setProto(resp *http.Response, proto string) {
+	switch proto {
+	case ProtoHTTP2:
+		resp.Proto, resp.ProtoMajor, resp.ProtoMinor = "HTTP/2.0", 2, 0
+	case ProtoHTTP3:
+		resp.Proto, resp.ProtoMajor, resp.ProtoMinor = "HTTP/3.0", 3, 0
+	}
+}
+
+// AltSvc alternative service advertised by Alt-Svc header, e.g. `h3=":443"; ma=86400`
+type AltSvc struct {
+	Protocol string `json:"protocol"` // alpn id, e.g. h3, h3-29, h2
+	Host     string `json:"host,omitempty"`
+	Port     string `json:"port"`
+	MaxAge   int    `json:"max_age,omitempty"` // seconds, 0 if not specified
+}
+
+// ParseAltSvc parse Alt-Svc header value, return nil for `clear`
+func ParseAltSvc(value string) []*AltSvc {
+	var svcs []*AltSvc
+	for _, m := range AltSvcRegexp.FindAllStringSubmatch(value, -1) {
+		svc := &AltSvc{Protocol: m[1]}
+		if i := strings.LastIndex(m[2], ":"); i != -1 {
+			svc.Host, svc.Port = m[2][:i], m[2][i+1:]
+		} else {
+			svc.Port = m[2]
+		}
+		if ma := AltSvcMaRegexp.FindStringSubmatch(m[3]); ma != nil {
+			svc.MaxAge, _ = strconv.Atoi(ma[1])
+		}
+		svcs = append(svcs, svc)
+	}
+	return svcs
+}
+
+// AltSvc return alternative services advertised by response
+func (content *Content) AltSvc() []*AltSvc {
+	var svcs []*AltSvc
+	for _, value := range content.GetHeaderValues("Alt-Svc") {
+		svcs = append(svcs, ParseAltSvc(value)...)
+	}
+	return svcs
+}
+
+// SupportHTTP3 return true if response is received over http/3 or http/3 is advertised by Alt-Svc
+func (content *Content) SupportHTTP3() bool {
+	if content.Proto == ProtoHTTP3 {
+		return true
+	}
+	for _, svc := range content.AltSvc() {
+		if strings.HasPrefix(svc.Protocol, "h3") {
+			return true
+		}
+	}
+	return false
+}
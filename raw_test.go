@@ -0,0 +1,70 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestNewResponseWithRaw_HTTP2(t *testing.T) {
+	h1 := NewResponseWithRaw([]byte("HTTP/1.1 200 OK\r\nServer: nginx\r\nContent-Type: text/html\r\nAlt-Svc: h3=\":443\"; ma=86400, h3-29=\"alt.example.com:8443\"\r\n\r\n<title>a</title>"))
+	h2 := NewResponseWithRaw([]byte("HTTP/2 200\ncontent-type: text/html\nserver: nginx\nalt-svc: h3=\":443\"; ma=86400, h3-29=\"alt.example.com:8443\"\n\n<title>a</title>"))
+	pseudo := NewResponseWithRaw([]byte(":status: 200\r\ncontent-type: text/html\r\nserver: nginx\r\nalt-svc: h3=\":443\"; ma=86400, h3-29=\"alt.example.com:8443\"\r\n\r\n<title>a</title>"))
+	if h1 == nil || h2 == nil || pseudo == nil {
+		t.Fatal("failed to parse raw")
+	}
+	if h1.Proto != ProtoHTTP11 || h2.Proto != ProtoHTTP2 || pseudo.Proto != ProtoHTTP2 {
+		t.Errorf("unexpected proto %s %s %s", h1.Proto, h2.Proto, pseudo.Proto)
+	}
+	if !bytes.Equal(h1.Raw, h2.Raw) || !bytes.Equal(h1.Raw, pseudo.Raw) {
+		t.Errorf("expect the same canonical raw, got\n%q\n%q\n%q", h1.Raw, h2.Raw, pseudo.Raw)
+	}
+	if !bytes.HasPrefix(h2.Raw, []byte("HTTP/1.1 200 OK\r\nAlt-Svc:")) || h2.Title != "a" {
+		t.Errorf("unexpected raw %q", h2.Raw)
+	}
+
+	svcs := h2.AltSvc()
+	if len(svcs) != 2 || svcs[0].Protocol != "h3" || svcs[0].Port != "443" || svcs[0].MaxAge != 86400 ||
+		svcs[1].Host != "alt.example.com" || svcs[1].Port != "8443" {
+		t.Errorf("unexpected alt-svc %+v %+v", svcs[0], svcs[1])
+	}
+	if !h2.SupportHTTP3() || len(ParseAltSvc("clear")) != 0 {
+		t.Error("unexpected http3 support")
+	}
+}
+
+func TestReadContent_Truncated(t *testing.T) {
+	raw := []byte("HTTP/1.1 200 OK\r\nContent-Length: 10\r\n\r\n0123456789")
+	resp, _ := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), nil)
+	content := ReadContent(resp, 4)
+	if !content.Truncated || content.ReadLength != 4 || content.ContentLength != 10 || string(content.Body) != "0123" {
+		t.Errorf("unexpected truncated content %+v", content)
+	}
+
+	resp, _ = http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), nil)
+	if content = ReadContent(resp, 10); content.Truncated || content.ReadLength != 10 {
+		t.Errorf("unexpected content %+v", content)
+	}
+}
+
+func TestReadContent_StreamedHashes(t *testing.T) {
+	raws := [][]byte{
+		[]byte("HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=gbk\r\nServer: nginx\r\n\r\n<title>\xc4\xe3\xba\xc3</title>"),
+		[]byte("HTTP/1.1 204 No Content\r\n\r\n"),
+		append([]byte("HTTP/1.1 200 OK\r\n\r\n"), bytes.Repeat([]byte("large body "), 10000)...),
+	}
+	for i, raw := range raws {
+		for _, size := range []int64{0, 16} {
+			resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := &Response{Content: ReadContent(resp, size)}
+			r.Hash()
+			if expect := NewHashes(r.Origin); *r.Hashes != *expect {
+				t.Errorf("case %d size %d: streamed hashes differ\n%+v\n%+v", i, size, r.Hashes, expect)
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package parsers
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	RedirectHTTP = "http" // 3xx with Location
+	RedirectMeta = "meta" // <meta http-equiv=refresh>
+	RedirectJS   = "js"   // location assignment in script
+)
+
+var (
+	MetaRefreshRegexp = regexp.MustCompile(`(?is)<meta\b[^>]*http-equiv\s*=\s*["']?refresh[^>]*>`)
+	RefreshURLRegexp  = regexp.MustCompile(`(?i)^\s*(\d*)(?:\.\d*)?\s*[;,]?\s*(?:url\s*=\s*)?["']?([^"']*)`)
+	SetTimeoutRegexp  = regexp.MustCompile(`(?s)setTimeout\s*\((.*?),\s*(\d+)\s*\)`)
+	JSLocationRegexp  = regexp.MustCompile(`(?i)\b(?:window\.|document\.|top\.|self\.|parent\.)?location(?:\.href)?\s*=\s*["']([^"']+)["']|\blocation\.(?:replace|assign)\(\s*["']([^"']+)["']\s*\)`)
+)
+
+// RedirectHop a response before the final one in the redirect chain
+type RedirectHop struct {
+	Method     string         `json:"method"`
+	URL        string         `json:"url"`
+	StatusCode int            `json:"status"`
+	Location   string         `json:"location,omitempty"` // resolved against URL
+	Resp       *http.Response `json:"-"`
+	*Content
+}
+
+func NewRedirectHop(resp *http.Response) *RedirectHop {
+	hop := &RedirectHop{
+		StatusCode: resp.StatusCode,
+		Resp:       resp,
+		Content:    ReadContent(resp, 0),
+	}
+	hop.SetTLS(resp.TLS)
+	if resp.Request != nil {
+		hop.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			hop.URL = resp.Request.URL.String()
+		}
+	}
+	if location, err := resp.Location(); err == nil {
+		hop.Location = location.String()
+	}
+	return hop
+}
+
+// NewRedirectHops walk resp.Request.Response, return hops in chronological order, the first request first
+func NewRedirectHops(resp *http.Response) []*RedirectHop {
+	var hops []*RedirectHop
+	for resp.Request != nil && resp.Request.Response != nil {
+		resp = resp.Request.Response
+		hops = append([]*RedirectHop{NewRedirectHop(resp)}, hops...)
+	}
+	return hops
+}
+
+type ClientRedirect struct {
+	Type  string `json:"type"` // RedirectMeta or RedirectJS
+	URL   string `json:"url"`
+	Delay int    `json:"delay"` // milliseconds
+}
+
+func (c *ClientRedirect) String() string {
+	if c.Delay > 0 {
+		return c.Type + ":" + c.URL + "(" + strconv.Itoa(c.Delay) + "ms)"
+	}
+	return c.Type + ":" + c.URL
+}
+
+type ClientRedirects []*ClientRedirect
+
+func (cs ClientRedirects) String() string {
+	var s strings.Builder
+	for _, c := range cs {
+		s.WriteString("[ " + c.String() + " ]")
+	}
+	if s.Len() > 0 {
+		s.WriteString(" ")
+	}
+	return s.String()
+}
+
+// ExtractClientRedirects extract meta refresh redirects, then javascript location redirects from body.
+// targets are resolved against base if not nil, the delay of javascript redirect is taken from the enclosing setTimeout
+func ExtractClientRedirects(body []byte, base *url.URL) ClientRedirects {
+	var redirects ClientRedirects
+	seen := make(map[string]bool)
+	add := func(typ, target string, delay int) {
+		target = strings.TrimSpace(target)
+		if target == "" || strings.HasPrefix(strings.ToLower(target), "javascript:") {
+			return
+		}
+		target = resolveURL(base, target)
+		if seen[typ+target] {
+			return
+		}
+		seen[typ+target] = true
+		redirects = append(redirects, &ClientRedirect{Type: typ, URL: target, Delay: delay})
+	}
+
+	content := string(body)
+	for _, tag := range MetaRefreshRegexp.FindAllString(content, -1) {
+		if m := RefreshURLRegexp.FindStringSubmatch(ParseAttributes(tag)["content"]); m != nil {
+			seconds, _ := strconv.Atoi(m[1])
+			add(RedirectMeta, m[2], seconds*1000)
+		}
+	}
+	for _, script := range ScriptRegexp.FindAllStringSubmatch(content, -1) {
+		timeouts := SetTimeoutRegexp.FindAllStringSubmatchIndex(script[1], -1)
+		for _, loc := range JSLocationRegexp.FindAllStringSubmatchIndex(script[1], -1) {
+			var target string
+			if loc[2] != -1 {
+				target = script[1][loc[2]:loc[3]]
+			} else {
+				target = script[1][loc[4]:loc[5]]
+			}
+			var delay int
+			for _, t := range timeouts {
+				if loc[0] >= t[2] && loc[1] <= t[3] {
+					delay, _ = strconv.Atoi(script[1][t[4]:t[5]])
+					break
+				}
+			}
+			add(RedirectJS, target, delay)
+		}
+	}
+	return redirects
+}
+
+// MatchClientRedirect return type and target of the first client side redirect in body, target is not resolved
+func MatchClientRedirect(body []byte) (string, string) {
+	if redirects := ExtractClientRedirects(body, nil); len(redirects) > 0 {
+		return redirects[0].Type, redirects[0].URL
+	}
+	return "", ""
+}
+
+// ClientRedirects return client side redirects in body, resolved against response url
+func (r *Response) ClientRedirects() ClientRedirects {
+	return ExtractClientRedirects(r.Body, r.URL())
+}
+
+// FrontURL return the url first requested if redirected, otherwise empty
+func (r *Response) FrontURL() string {
+	if len(r.History) == 0 {
+		return ""
+	}
+	return r.History[0].URL
+}
+
+// RedirectURL return the resolved Location of response, or the target of client side redirect
+func (r *Response) RedirectURL() string {
+	if r.Resp != nil {
+		if location, err := r.Resp.Location(); err == nil {
+			return location.String()
+		}
+	}
+	if redirects := r.ClientRedirects(); len(redirects) > 0 {
+		return redirects[0].URL
+	}
+	return ""
+}
+
+// RedirectType return RedirectHTTP, RedirectMeta, RedirectJS, or empty if not redirect
+func (r *Response) RedirectType() string {
+	if r.Resp != nil && r.GetHeader("Location") != "" {
+		return RedirectHTTP
+	}
+	typ, _ := MatchClientRedirect(r.Body)
+	return typ
+}
+
+// Chain return urls of the redirect chain, including the final one
+func (r *Response) Chain() []string {
+	var urls []string
+	for _, hop := range r.History {
+		urls = append(urls, hop.URL)
+	}
+	if u := r.URL(); u != nil {
+		urls = append(urls, u.String())
+	}
+	return urls
+}
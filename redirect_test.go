@@ -0,0 +1,84 @@
+package parsers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestNewRedirectHops(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login", http.StatusFound)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/portal", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/portal", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><meta http-equiv="refresh" content="0; url='/app/'"></html>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	raw, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := NewResponse(raw, 0)
+	if len(resp.History) != 2 {
+		t.Fatalf("expect 2 hops, got %d", len(resp.History))
+	}
+	first, second := resp.History[0], resp.History[1]
+	if first.URL != server.URL+"/" || first.StatusCode != 302 || first.Location != server.URL+"/login" || first.Method != "GET" {
+		t.Errorf("unexpected first hop %+v", first)
+	}
+	if second.StatusCode != 301 || second.Location != server.URL+"/portal" {
+		t.Errorf("unexpected second hop %+v", second)
+	}
+	if resp.FrontURL() != server.URL+"/" || len(resp.Chain()) != 3 {
+		t.Errorf("unexpected chain %v", resp.Chain())
+	}
+	if resp.RedirectType() != RedirectMeta || resp.RedirectURL() != server.URL+"/app/" {
+		t.Errorf("unexpected client redirect %s %s", resp.RedirectType(), resp.RedirectURL())
+	}
+}
+
+func TestMatchClientRedirect(t *testing.T) {
+	typ, target := MatchClientRedirect([]byte(`<script>var a=1;window.location.href = "/index.do";</script>`))
+	if typ != RedirectJS || target != "/index.do" {
+		t.Errorf("unexpected js redirect %s %s", typ, target)
+	}
+	typ, target = MatchClientRedirect([]byte(`<script>top.location.replace('/home')</script>`))
+	if typ != RedirectJS || target != "/home" {
+		t.Errorf("unexpected js redirect %s %s", typ, target)
+	}
+	if typ, _ = MatchClientRedirect([]byte(`<script>var relocation = "x"</script>`)); typ != "" {
+		t.Errorf("unexpected redirect %s", typ)
+	}
+}
+
+func TestExtractClientRedirects(t *testing.T) {
+	base, _ := url.Parse("http://example.com/a/b.html")
+	body := []byte(`<html><head>
+<META HTTP-EQUIV="Refresh" CONTENT="3;URL=/portal/">
+<script>
+setTimeout(function () { window.location.href = "login.jsp"; }, 1500);
+document.location = 'javascript:void(0)';
+location.replace("/portal/")
+</script></head></html>`)
+	redirects := ExtractClientRedirects(body, base)
+	if len(redirects) != 3 {
+		t.Fatalf("expect 3 redirects, got %s", redirects.String())
+	}
+	expects := []ClientRedirect{
+		{Type: RedirectMeta, URL: "http://example.com/portal/", Delay: 3000},
+		{Type: RedirectJS, URL: "http://example.com/a/login.jsp", Delay: 1500},
+		{Type: RedirectJS, URL: "http://example.com/portal/"},
+	}
+	for i, expect := range expects {
+		if *redirects[i] != expect {
+			t.Errorf("expect %s, got %s", expect.String(), redirects[i].String())
+		}
+	}
+}
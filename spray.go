@@ -1,6 +1,7 @@
 package parsers
 
 import (
+	"bytes"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -69,6 +70,54 @@ func (s SpraySource) Name() string {
 	}
 }
 
+func (s SpraySource) String() string {
+	return s.Name()
+}
+
+// ParseSpraySource return the source of the name, the inverse of Name
+func ParseSpraySource(name string) (SpraySource, error) {
+	for s := CheckSource; s <= AppendRuleSource; s++ {
+		if s.Name() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown spray source %q", name)
+}
+
+func (s SpraySource) MarshalText() ([]byte, error) {
+	return []byte(s.Name()), nil
+}
+
+// UnmarshalText accept both source name and legacy int value
+func (s *SpraySource) UnmarshalText(text []byte) error {
+	if i, err := strconv.Atoi(string(text)); err == nil {
+		*s = SpraySource(i)
+		return nil
+	}
+	if string(text) == "unknown" {
+		*s = 0
+		return nil
+	}
+	source, err := ParseSpraySource(string(text))
+	if err != nil {
+		return err
+	}
+	*s = source
+	return nil
+}
+
+func (s SpraySource) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Name())
+}
+
+// UnmarshalJSON accept both `"word"` and legacy `7`
+func (s *SpraySource) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return s.UnmarshalText(bytes.Trim(data, "\""))
+}
+
 type SprayResult struct {
 	Number       int               `json:"number"`
 	Parent       int               `json:"parent"`
@@ -85,13 +134,16 @@ type SprayResult struct {
 	Status       int               `json:"status"`
 	Spended      int64             `json:"spend"` // 耗时, 毫秒
 	ContentType  string            `json:"content_type"`
+	Kind         ContentKind       `json:"kind,omitempty"`
 	Title        string            `json:"title"`
 	Frameworks   common.Frameworks `json:"frameworks"`
 	Extracteds   Extracteds        `json:"extracts"`
+	Findings     Findings          `json:"findings,omitempty"`
+	Redirects    ClientRedirects   `json:"client_redirects,omitempty"`
 	ErrString    string            `json:"error"`
 	Reason       string            `json:"reason"`
 	Source       SpraySource       `json:"source"`
-	From         SpraySource       `yaml:"from"`
+	From         SpraySource       `json:"from" yaml:"from"`
 	ReqDepth     int               `json:"depth"`
 	Distance     uint8             `json:"distance"`
 	Unique       uint16            `json:"unique"`
@@ -106,6 +158,8 @@ func (bl *SprayResult) Get(key string) string {
 		return bl.Host
 	case "content_type", "type":
 		return bl.ContentType
+	case "kind":
+		return bl.Kind.Name()
 	case "title":
 		return bl.Title
 	case "redirect":
@@ -124,7 +178,19 @@ func (bl *SprayResult) Get(key string) string {
 		}
 	case "mmh3":
 		if bl.Hashes != nil {
-			return bl.Hashes.BodySimhash
+			return bl.Hashes.BodyMmh3
+		} else {
+			return ""
+		}
+	case "ja3s":
+		if bl.Hashes != nil {
+			return bl.Hashes.JA3S
+		} else {
+			return ""
+		}
+	case "cert_hash", "cert_sha256":
+		if bl.Hashes != nil {
+			return bl.Hashes.CertSHA256
 		} else {
 			return ""
 		}
@@ -142,14 +208,26 @@ func (bl *SprayResult) Get(key string) string {
 		return bl.From.Name()
 	case "unique":
 		return strconv.Itoa(int(bl.Unique))
-	case "extract":
+	case "depth":
+		return strconv.Itoa(bl.ReqDepth)
+	case "extract", "extracts":
 		return bl.Extracteds.String()
-	case "frame", "framework":
+	case "finding", "findings":
+		return bl.Findings.String()
+	case "client_redirect", "client_redirects":
+		return bl.Redirects.String()
+	case "frame", "framework", "frameworks":
 		var s strings.Builder
 		for _, f := range bl.Frameworks {
 			s.WriteString(" [" + f.String() + "]")
 		}
 		return s.String()
+	case "tag", "tags":
+		var tags []string
+		for _, f := range bl.Frameworks {
+			tags = append(tags, f.Tags...)
+		}
+		return strings.Join(tags, ",")
 	case "cpe", "fsb":
 		return strings.Join(bl.Frameworks.CPE(), ",")
 	case "uri":
@@ -163,6 +241,51 @@ func (bl *SprayResult) Get(key string) string {
 	}
 }
 
+// Filter match single condition, supported operators: ::, ==, !:, !=, >=, <=, >, <, ~=
+func (bl *SprayResult) Filter(k, v, op string) bool {
+	return bl.Match(&FilterCondition{Key: k, Op: op, Value: v})
+}
+
+func (bl *SprayResult) Match(cond *FilterCondition) bool {
+	switch cond.Key {
+	case "frame", "framework", "frameworks":
+		if cond.Op == "==" || cond.Op == "!=" {
+			return bl.hasFramework(cond.Value) == (cond.Op == "==")
+		}
+	case "tag", "tags":
+		if cond.Op == "==" || cond.Op == "!=" {
+			return bl.Frameworks.HasTag(cond.Value) == (cond.Op == "==")
+		}
+	case "extract", "extracts":
+		if cond.Op == "==" || cond.Op == "!=" {
+			return bl.hasExtracted(cond.Value) == (cond.Op == "==")
+		}
+	case "finding", "findings":
+		if cond.Op == "==" || cond.Op == "!=" {
+			return bl.Findings.Has(cond.Value) == (cond.Op == "==")
+		}
+	}
+	return cond.Match(bl.Get(cond.Key))
+}
+
+func (bl *SprayResult) hasFramework(name string) bool {
+	for _, f := range bl.Frameworks {
+		if strings.EqualFold(f.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (bl *SprayResult) hasExtracted(name string) bool {
+	for _, e := range bl.Extracteds {
+		if strings.EqualFold(e.Name, name) && len(e.ExtractResult) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (bl *SprayResult) FramesColorString() string {
 	var s strings.Builder
 	for _, f := range bl.Frameworks {
@@ -176,7 +299,7 @@ func (bl *SprayResult) FramesColorString() string {
 }
 
 func (bl *SprayResult) Additional(key string) string {
-	if key == "frame" || key == "extract" {
+	if key == "frame" || key == "extract" || key == "finding" || key == "client_redirect" {
 		return bl.Get(key)
 	} else if v := bl.Get(key); v != "" {
 		return " [" + v + "]"
@@ -256,6 +379,8 @@ func (bl *SprayResult) ColorString() string {
 
 	line.WriteString(bl.FramesColorString())
 	line.WriteString(logs.Cyan(bl.Additional("extract")))
+	line.WriteString(logs.Yellow(bl.Additional("finding")))
+	line.WriteString(logs.WhiteLine(bl.Additional("client_redirect")))
 	if len(bl.Extracteds) > 0 {
 		for _, e := range bl.Extracteds {
 			line.WriteString("\n  " + e.Name + " (" + strconv.Itoa(len(e.ExtractResult)) + ") items : \n\t")
@@ -304,6 +429,8 @@ func (bl *SprayResult) String() string {
 
 	line.WriteString(bl.Additional("frame"))
 	line.WriteString(bl.Additional("extract"))
+	line.WriteString(bl.Additional("finding"))
+	line.WriteString(bl.Additional("client_redirect"))
 	if len(bl.Extracteds) > 0 {
 		for _, e := range bl.Extracteds {
 			line.WriteString("\n  " + e.Name + " (" + strconv.Itoa(len(e.ExtractResult)) + ") items : \n\t")
@@ -333,7 +460,7 @@ func (sr *SprayResult) ToCSV() string {
 	//	"FrontURL", "Status", "Spended", "ContentType", "Title",
 	//	"Frameworks", "Extracteds", "ErrString", "Reason", "Source",
 	//	"ReqDepth", "Distance", "Unique", "BodySimhash", "BodyMd5",
-	//	"BodyMmh3",
+	//	"BodyMmh3", "JA3S", "CertSHA256",
 	//}
 	//
 	//// Write the header to the CSV writer
@@ -367,6 +494,8 @@ func (sr *SprayResult) ToCSV() string {
 		sr.Hashes.BodySimhash,
 		sr.Hashes.BodyMd5,
 		sr.Hashes.BodyMmh3,
+		sr.Hashes.JA3S,
+		sr.Hashes.CertSHA256,
 	}
 
 	// Write the record to the CSV writer
@@ -379,6 +508,56 @@ func (sr *SprayResult) ToCSV() string {
 
 // Write the record to the CSV write
 
+type SprayResults []*SprayResult
+
+// FilterWithString filter results with query, e.g. `status>=300 && status<400 || title~=(?i)login`
+func (rs SprayResults) FilterWithString(name string) SprayResults {
+	if name == "focus" {
+		var results SprayResults
+		for _, r := range rs {
+			if r.Frameworks.IsFocus() {
+				results = append(results, r)
+			}
+		}
+		return results
+	}
+
+	query, err := ParseFilterQuery(name)
+	if err != nil {
+		logs.Log.Error(err.Error())
+		return nil
+	}
+	return rs.FilterWithQuery(query)
+}
+
+func (rs SprayResults) FilterWithQuery(query FilterQuery) SprayResults {
+	var results SprayResults
+	for _, r := range rs {
+		if query.Match(r.Match) {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+func (rs SprayResults) Filter(k, v, op string) SprayResults {
+	var results SprayResults
+	for _, r := range rs {
+		if r.Filter(k, v, op) {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+func (rs SprayResults) GetValues(key string) []string {
+	values := make([]string, len(rs))
+	for i, r := range rs {
+		values[i] = r.Get(key)
+	}
+	return values
+}
+
 func padding(s string, size int) string {
 	if len(s) >= size {
 		return s
@@ -0,0 +1,103 @@
+package parsers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chainreactors/fingers/common"
+)
+
+func newTestSprayResults() SprayResults {
+	return SprayResults{
+		{UrlString: "http://example.com/", Status: 200, BodyLength: 1024, Title: "Welcome", Source: CheckSource, ContentType: "html", Kind: HTMLKind,
+			Frameworks: common.Frameworks{"nginx": common.NewFramework("nginx", common.FrameFromDefault)}},
+		{UrlString: "http://example.com/login", Status: 302, BodyLength: 0, Title: "Login Portal", Source: WordSource, ReqDepth: 1,
+			Extracteds: Extracteds{{Name: "url", ExtractResult: []string{"/admin"}}}},
+		{UrlString: "http://example.com/admin", Status: 403, BodyLength: 300, Title: "Forbidden", Source: RedirectSource, ReqDepth: 2, Kind: ErrorPageKind},
+	}
+}
+
+func TestSprayResults_FilterWithString(t *testing.T) {
+	rs := newTestSprayResults()
+	cases := map[string][]string{
+		"status>=300 && status<400":            {"http://example.com/login"},
+		"length>100":                           {"http://example.com/", "http://example.com/admin"},
+		"title~=(?i)^login":                    {"http://example.com/login"},
+		"source==word || source==redir":        {"http://example.com/login", "http://example.com/admin"},
+		"depth>=1 && extract==url":             {"http://example.com/login"},
+		"framework==nginx":                     {"http://example.com/"},
+		"content_type::htm && status!=404":     {"http://example.com/"},
+		"kind==error || kind==html":            {"http://example.com/", "http://example.com/admin"},
+		`title~="^(Login Portal||Forbidden)$"`: {"http://example.com/login", "http://example.com/admin"},
+		`title~='Portal$' && status==302`:      {"http://example.com/login"},
+	}
+	for query, expect := range cases {
+		got := rs.FilterWithString(query).GetValues("url")
+		if len(got) != len(expect) {
+			t.Errorf("%s: expect %v, got %v", query, expect, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != expect[i] {
+				t.Errorf("%s: expect %v, got %v", query, expect, got)
+			}
+		}
+	}
+}
+
+func TestParseFilterQuery(t *testing.T) {
+	if _, err := ParseFilterQuery("status"); err == nil {
+		t.Error("expect error for condition without operator")
+	}
+	if _, err := ParseFilterQuery("title~=(["); err == nil {
+		t.Error("expect error for illegal regexp")
+	}
+	if _, err := ParseFilterQuery("stauts>=100"); err == nil {
+		t.Error("expect error for unknown key")
+	}
+	if _, err := ParseFilterQuery(`title~="a||b`); err == nil {
+		t.Error("expect error for unterminated quote")
+	}
+
+	query, err := ParseFilterQuery(`title~="a||b" && status>=200 || url::'x&&y'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(query) != 2 || len(query[0]) != 2 || query[0][0].Value != "a||b" || query[1][0].Value != "x&&y" {
+		t.Errorf("unexpected query %v", query)
+	}
+}
+
+func TestSpraySource_JSON(t *testing.T) {
+	bs, err := json.Marshal(&SprayResult{Source: WordSource, From: CrawlSource})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bs), `"source":"word"`) || !strings.Contains(string(bs), `"from":"crawl"`) {
+		t.Errorf("unexpected json: %s", bs)
+	}
+
+	for _, data := range []string{string(bs), `{"source":7,"From":5}`} {
+		var r SprayResult
+		if err := json.Unmarshal([]byte(data), &r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Source != WordSource || r.From != CrawlSource {
+			t.Errorf("%s: got source %s, from %s", data, r.Source, r.From)
+		}
+	}
+
+	if _, err := ParseSpraySource("nonexistent"); err == nil {
+		t.Error("expect error for unknown source")
+	}
+}
+
+func TestSprayResult_String(t *testing.T) {
+	r := &SprayResult{UrlString: "http://example.com/", Status: 200,
+		Findings:  Findings{NewFinding("missing-csp", "info", "")},
+		Redirects: ClientRedirects{{Type: RedirectMeta, URL: "/x"}}}
+	if s := r.String(); !strings.Contains(s, "[ missing-csp ] [ meta:/x ]") {
+		t.Errorf("findings and client redirects should be separated, got %q", s)
+	}
+}
@@ -0,0 +1,55 @@
+package parsers
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	SvgRegexp           = regexp.MustCompile(`(?is)<svg\b.*?</svg>`)
+	DocumentTitleRegexp = regexp.MustCompile("(?i)document\\.title\\s*=\\s*(?:\"([^\"]*)\"|'([^']*)'|`([^`]*)`)")
+	CDATARegexp         = regexp.MustCompile(`(?s)^<!\[CDATA\[(.*)\]\]>$`)
+)
+
+// CharacterLength the max runes of snippet returned by MatchCharacter
+var CharacterLength = 32
+
+// ExtractTitle extract title from html or xml body, by priority:
+// html <title> (svg titles are ignored), meta og:title/twitter:title, document.title assignment in script, svg/xml <title>
+func ExtractTitle(body []byte) (string, bool) {
+	content := string(body)
+	if title, ok := matchTitleTag(SvgRegexp.ReplaceAllString(content, "")); ok {
+		return title, true
+	}
+
+	for _, meta := range MetaRegexp.FindAllString(content, -1) {
+		attrs := ParseAttributes(meta)
+		key := strings.ToLower(attrs["property"] + attrs["name"])
+		if key == "og:title" || key == "twitter:title" {
+			if title := CleanText(attrs["content"]); title != "" {
+				return title, true
+			}
+		}
+	}
+
+	if m := DocumentTitleRegexp.FindStringSubmatch(content); m != nil {
+		if title := CleanText(m[1] + m[2] + m[3]); title != "" {
+			return title, true
+		}
+	}
+
+	return matchTitleTag(content)
+}
+
+func matchTitleTag(content string) (string, bool) {
+	for _, m := range TitleRegexp.FindAllStringSubmatch(content, -1) {
+		title := strings.TrimSpace(m[1])
+		if sub := CDATARegexp.FindStringSubmatch(title); sub != nil {
+			title = sub[1]
+		}
+		if title = CleanText(TagRegexp.ReplaceAllString(title, " ")); title != "" {
+			return title, true
+		}
+	}
+	return "", false
+}
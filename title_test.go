@@ -0,0 +1,39 @@
+package parsers
+
+import "testing"
+
+func TestExtractTitle(t *testing.T) {
+	cases := map[string]string{
+		`<html><head><title>Simple</title></head></html>`: "Simple",
+		`<title id="x" data-i18n='t'>  Admin
+		   Console  </title>`: "Admin Console",
+		`<title>Tom &amp; Jerry &#8211; Home</title>`:                                        "Tom & Jerry – Home",
+		`<svg><title>icon</title></svg><head><title>Page</title></head>`:                     "Page",
+		`<svg xmlns="http://www.w3.org/2000/svg"><title>Only SVG</title></svg>`:              "Only SVG",
+		`<meta content="Graph Title" property="og:title"><title></title>`:                    "Graph Title",
+		`<script>document.title = 'Dynamic';</script>`:                                       "Dynamic",
+		`<?xml version="1.0"?><rss><channel><title><![CDATA[Feed]]></title></channel></rss>`: "Feed",
+		`<html><body>no title here</body></html>`:                                            "",
+	}
+	for body, expect := range cases {
+		title, _ := ExtractTitle([]byte(body))
+		if title != expect {
+			t.Errorf("%s: expect %q, got %q", body, expect, title)
+		}
+	}
+}
+
+func TestMatchCharacter(t *testing.T) {
+	raw := []byte("HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<html><style>body{}</style><script>var a=1;</script><h1>It  works!</h1><p>The default page</p></html>")
+	if got := MatchCharacter(raw); got != "It works! The default page" {
+		t.Errorf("unexpected snippet %q", got)
+	}
+	if got := MatchCharacter([]byte("\x89PNG\r\n\x1a\n\x00\x00")); got != "" {
+		t.Errorf("expect empty snippet for binary, got %q", got)
+	}
+
+	resp := NewResponseWithRaw([]byte("HTTP/1.1 404 Not Found\r\nContent-Type: text/plain\r\n\r\nnot found"))
+	if resp.HasTitle || resp.Title != "not found" {
+		t.Errorf("unexpected title %q", resp.Title)
+	}
+}
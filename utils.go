@@ -1,15 +1,16 @@
 package parsers
 
 import (
+	"bytes"
+	"github.com/chainreactors/utils/httputils"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 var (
-	TitleRegexp  = regexp.MustCompile("(?Uis)<title>(.*)</title>")
-	ServerRegexp = regexp.MustCompile("(?i)Server: ([\x20-\x7e]+)")
-	//XPBRegexp           = regexp.MustCompile("(?i)X-Powered-By: ([\x20-\x7e]+)")
-	//SessionRegexp       = regexp.MustCompile("(?i) (.*SESS.*?ID)")
+	TitleRegexp         = regexp.MustCompile("(?Uis)<title\\b[^>]*>(.*)</title>")
+	ServerRegexp        = regexp.MustCompile("(?i)Server: ([\x20-\x7e]+)")
 	HeaderCharsetRegexp = regexp.MustCompile("(?i)Content-Type:.*charset=(.+)")
 	BodyCharsetRegexp   = regexp.MustCompile("(?i)<meta.*?charset=[\"']?(.*?)[\"' >]")
 )
@@ -40,59 +41,31 @@ func MatchCharset(content []byte) string {
 }
 
 func MatchTitle(content []byte) string {
-	title, ok := MatchOne(TitleRegexp, content)
+	if body, _, ok := httputils.SplitHttpRaw(content); ok && bytes.HasPrefix(content, []byte("HTTP/")) {
+		content = body
+	}
+	title, ok := ExtractTitle(content)
 	if ok {
 		return title
 	}
 	return ""
 }
 
+// MatchCharacter return a snippet of the visible text, used as title when there is no title. binary content returns empty
 func MatchCharacter(content []byte) string {
-	if len(content) > 13 {
-		return string(content[0:13])
-	} else {
-		return string(content)
+	if body, _, ok := httputils.SplitHttpRaw(content); ok && bytes.HasPrefix(content, []byte("HTTP/")) {
+		content = body
+	}
+	if !utf8.Valid(content) || bytes.IndexByte(content, 0) != -1 {
+		return ""
 	}
-}
-
-//func MatchLanguage(resp *http.Response) string {
-//	var powered string
-//	powered = resp.Header.Get("X-Powered-By")
-//	if powered != "" {
-//		return powered
-//	}
-//
-//	cookies := getCookies(resp)
-//	if cookies["JSESSIONID"] != "" {
-//		return "JAVA"
-//	} else if cookies["ASP.NET_SessionId"] != "" {
-//		return "ASP"
-//	} else if cookies["PHPSESSID"] != "" {
-//		return "PHP"
-//	} else {
-//		return ""
-//	}
-//}
 
-//func MatchLanguageWithRaw(content []byte) string {
-//	powered, ok := MatchOne(XPBRegexp, content)
-//	if ok {
-//		return powered
-//	}
-//
-//	sessionid, ok := MatchOne(SessionRegexp, content)
-//	if ok {
-//		switch sessionid {
-//		case "JSESSIONID":
-//			return "JAVA"
-//		case "ASP.NET_SessionId":
-//			return "ASP.NET"
-//		case "PHPSESSID":
-//			return "PHP"
-//		}
-//	}
-//	return ""
-//}
+	text := StripTags(string(content))
+	if utf8.RuneCountInString(text) > CharacterLength {
+		text = string([]rune(text)[:CharacterLength])
+	}
+	return text
+}
 
 func notContains(s, substr string) bool {
 	return !strings.Contains(s, substr)
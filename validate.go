@@ -0,0 +1,271 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// BroadMinLength regexp without literal and shorter than it is reported as overly broad, e.g. `\w+`
+var BroadMinLength = 8
+
+var (
+	ErrEmptyRegexps   = errors.New("no regexp")
+	ErrEmptyRegexp    = errors.New("empty regexp")
+	ErrEmptyRules     = errors.New("no rule")
+	ErrEmptyRule      = errors.New("empty rule")
+	ErrUnknownType    = errors.New("unknown type")
+	ErrNoCaptureGroup = errors.New("no such capture group")
+	ErrDuplicateName  = errors.New("duplicate name")
+	ErrMatchEmpty     = errors.New("matches empty string")
+	ErrOverlyBroad    = errors.New("overly broad, no literal and too short")
+	ErrNestedQuantity = errors.New("nested quantifiers, catastrophic backtracking in other regexp engines")
+)
+
+// ExtractorError error of extractor, Index is the index of rule in Field, -1 if not related to a rule
+type ExtractorError struct {
+	File  string // pack file the extractor loaded from, if any
+	Group string
+	Name  string
+	Field string // regexps if empty
+	Index int
+	Err   error
+}
+
+func (e *ExtractorError) Error() string {
+	var prefix string
+	if e.Group != "" {
+		prefix = e.Group + "."
+	}
+	var msg string
+	if e.Index < 0 {
+		msg = fmt.Sprintf("extractor %s%s: %s", prefix, e.Name, e.Err.Error())
+	} else {
+		field := e.Field
+		if field == "" {
+			field = "regexps"
+		}
+		msg = fmt.Sprintf("extractor %s%s %s[%d]: %s", prefix, e.Name, field, e.Index, e.Err.Error())
+	}
+	if e.File != "" {
+		return e.File + ": " + msg
+	}
+	return msg
+}
+
+func (e *ExtractorError) Unwrap() error {
+	return e.Err
+}
+
+// ExtractorErrors errors of extractor pack, the extractors returned along with it are still usable
+type ExtractorErrors []error
+
+func (errs ExtractorErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate lint the extractor, report empty regexps, compile errors, missing Capture group, patterns matching empty string,
+// overly broad patterns and nested quantifiers. rules of other types are checked for emptiness and compile errors
+func (e *Extractor) Validate() []error {
+	switch e.Type {
+	case "", RegexExtractor:
+	case KeywordExtractor, JSONExtractor, XPathExtractor, CSSExtractor, HeaderExtractor:
+		return e.validateRules()
+	default:
+		return []error{&ExtractorError{Name: e.Name, Index: -1, Err: ErrUnknownType}}
+	}
+
+	var errs []error
+	if len(e.Regexps) == 0 {
+		errs = append(errs, &ExtractorError{Name: e.Name, Index: -1, Err: ErrEmptyRegexps})
+	}
+	for i := range e.Regexps {
+		pattern := e.pattern(i)
+		if pattern == "" {
+			errs = append(errs, &ExtractorError{Name: e.Name, Index: i, Err: ErrEmptyRegexp})
+			continue
+		}
+		r, err := regexp.Compile(pattern)
+		if err != nil {
+			errs = append(errs, &ExtractorError{Name: e.Name, Index: i, Err: err})
+			continue
+		}
+		if e.captureIndex(r) < 0 {
+			errs = append(errs, &ExtractorError{Name: e.Name, Index: i, Err: ErrNoCaptureGroup})
+		}
+		re, err := syntax.Parse(pattern, syntax.Perl)
+		if err != nil {
+			continue
+		}
+		re = re.Simplify()
+		if r.MatchString("") {
+			errs = append(errs, &ExtractorError{Name: e.Name, Index: i, Err: ErrMatchEmpty})
+		} else if literalLength(re) == 0 && minLength(re) < BroadMinLength {
+			errs = append(errs, &ExtractorError{Name: e.Name, Index: i, Err: ErrOverlyBroad})
+		}
+		if hasNestedQuantifier(re, false) {
+			errs = append(errs, &ExtractorError{Name: e.Name, Index: i, Err: ErrNestedQuantity})
+		}
+	}
+	return errs
+}
+
+// validateRules report empty rules and compile errors of the non-regex types
+func (e *Extractor) validateRules() []error {
+	var errs []error
+	rules := e.rules()
+	if len(rules) == 0 {
+		errs = append(errs, &ExtractorError{Name: e.Name, Field: e.field(), Index: -1, Err: ErrEmptyRules})
+	}
+	empty := make(map[int]bool)
+	for i, rule := range rules {
+		if strings.TrimSpace(rule) == "" {
+			empty[i] = true
+			errs = append(errs, &ExtractorError{Name: e.Name, Field: e.field(), Index: i, Err: ErrEmptyRule})
+		}
+	}
+	// compile a copy, Validate should not change the extractor
+	compiled := *e
+	for _, err := range compiled.compile() {
+		if !empty[err.(*ExtractorError).Index] {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Compile compile all extractors, return errors of invalid regexps, valid ones are still usable
+func (es Extractors) Compile() []error {
+	var errs []error
+	seen := make(map[*Extractor]bool)
+	for _, group := range es.groups() {
+		for _, e := range es[group] {
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+			if err := e.Compile(); err != nil {
+				err.(*ExtractorError).Group = group
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+// Validate lint all extractors and report duplicate names, the later one is reported as duplicate.
+// the same extractor indexed by name and tags is validated once
+func (es Extractors) Validate() []error {
+	var errs []error
+	groups := es.groups()
+
+	names := make(map[string]bool)
+	seen := make(map[*Extractor]bool)
+	for _, group := range groups {
+		for _, e := range es[group] {
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+			if names[e.Name] {
+				errs = append(errs, &ExtractorError{Group: group, Name: e.Name, Index: -1, Err: ErrDuplicateName})
+			} else {
+				names[e.Name] = true
+			}
+			for _, err := range e.Validate() {
+				err.(*ExtractorError).Group = group
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+// groups return sorted group names, to keep errors in stable order
+func (es Extractors) groups() []string {
+	groups := make([]string, 0, len(es))
+	for group := range es {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// minLength return the min length in runes of strings matched by re
+func minLength(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune)
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return 1
+	case syntax.OpCapture, syntax.OpPlus:
+		return minLength(re.Sub[0])
+	case syntax.OpRepeat:
+		return re.Min * minLength(re.Sub[0])
+	case syntax.OpConcat:
+		var n int
+		for _, sub := range re.Sub {
+			n += minLength(sub)
+		}
+		return n
+	case syntax.OpAlternate:
+		n := -1
+		for _, sub := range re.Sub {
+			if l := minLength(sub); n == -1 || l < n {
+				n = l
+			}
+		}
+		return n
+	default:
+		return 0
+	}
+}
+
+// literalLength return the min count of literal runes in strings matched by re
+func literalLength(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune)
+	case syntax.OpCapture, syntax.OpPlus:
+		return literalLength(re.Sub[0])
+	case syntax.OpRepeat:
+		return re.Min * literalLength(re.Sub[0])
+	case syntax.OpConcat:
+		var n int
+		for _, sub := range re.Sub {
+			n += literalLength(sub)
+		}
+		return n
+	case syntax.OpAlternate:
+		n := -1
+		for _, sub := range re.Sub {
+			if l := literalLength(sub); n == -1 || l < n {
+				n = l
+			}
+		}
+		return n
+	default:
+		return 0
+	}
+}
+
+// hasNestedQuantifier report unbounded repeat inside another unbounded repeat, e.g. `(a+)+`
+func hasNestedQuantifier(re *syntax.Regexp, inRepeat bool) bool {
+	unbounded := re.Op == syntax.OpStar || re.Op == syntax.OpPlus || (re.Op == syntax.OpRepeat && re.Max == -1)
+	if unbounded && inRepeat {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasNestedQuantifier(sub, inRepeat || unbounded) {
+			return true
+		}
+	}
+	return false
+}
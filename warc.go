@@ -0,0 +1,302 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"fmt"
+	"github.com/chainreactors/utils/httputils"
+	"io"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	WARCVersion = "WARC/1.1"
+
+	WARCTypeInfo     = "warcinfo"
+	WARCTypeResponse = "response"
+	WARCTypeRequest  = "request"
+	WARCTypeResource = "resource"
+	WARCTypeMetadata = "metadata"
+
+	WARCHTTPResponseType = "application/http;msgtype=response"
+)
+
+// WARCMaxRecordSize record with larger Content-Length is rejected by WARCReader
+var WARCMaxRecordSize int64 = 1 << 28
+
+// warcHeaderOrder the order and spelling of named fields written in record header, textproto canonicalizes WARC-* into Warc-*
+var warcHeaderOrder = []string{
+	"WARC-Type",
+	"WARC-Record-ID",
+	"WARC-Date",
+	"WARC-Target-URI",
+	"WARC-IP-Address",
+	"WARC-Warcinfo-ID",
+	"WARC-Concurrent-To",
+	"WARC-Payload-Digest",
+	"WARC-Block-Digest",
+	"Content-Type",
+	"Content-Length",
+}
+
+type WARCRecord struct {
+	Version string
+	Header  http.Header
+	Content []byte
+}
+
+func NewWARCRecord(typ string, content []byte) *WARCRecord {
+	record := &WARCRecord{
+		Version: WARCVersion,
+		Header:  make(http.Header),
+		Content: content,
+	}
+	record.Header.Set("WARC-Type", typ)
+	record.Header.Set("WARC-Record-ID", newWARCRecordID())
+	record.Header.Set("WARC-Date", time.Now().UTC().Format(time.RFC3339))
+	return record
+}
+
+func (rec *WARCRecord) Type() string {
+	return rec.Header.Get("WARC-Type")
+}
+
+func (rec *WARCRecord) RecordID() string {
+	return rec.Header.Get("WARC-Record-ID")
+}
+
+func (rec *WARCRecord) TargetURI() string {
+	return rec.Header.Get("WARC-Target-URI")
+}
+
+func (rec *WARCRecord) Date() time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, rec.Header.Get("WARC-Date"))
+	return t
+}
+
+// Response parse http response record into Response with hashes, return nil if record is not a http response
+func (rec *WARCRecord) Response() *Response {
+	if rec.Type() != WARCTypeResponse || !bytes.HasPrefix(rec.Content, []byte("HTTP/")) {
+		return nil
+	}
+	resp := NewResponseWithRaw(rec.Content)
+	if resp == nil {
+		return nil
+	}
+	if u, err := url.Parse(rec.TargetURI()); err == nil && resp.Resp != nil {
+		resp.Resp.Request = &http.Request{Method: http.MethodGet, URL: u, Header: make(http.Header)}
+	}
+	resp.Hash()
+	return resp
+}
+
+func (rec *WARCRecord) Bytes() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(rec.Version + "\r\n")
+	written := make(map[string]bool)
+	for _, k := range warcHeaderOrder {
+		ck := textproto.CanonicalMIMEHeaderKey(k)
+		written[ck] = true
+		for _, v := range rec.Header[ck] {
+			buf.WriteString(k + ": " + v + "\r\n")
+		}
+	}
+	keys := make([]string, 0, len(rec.Header))
+	for k := range rec.Header {
+		if !written[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range rec.Header[k] {
+			buf.WriteString(k + ": " + v + "\r\n")
+		}
+	}
+	buf.WriteString("\r\n")
+	buf.Write(rec.Content)
+	buf.WriteString("\r\n\r\n")
+	return buf.Bytes()
+}
+
+func newWARCRecordID() string {
+	var u [16]byte
+	_, _ = rand.Read(u[:])
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
+
+func warcDigest(content []byte) string {
+	sum := sha1.Sum(content)
+	return "sha1:" + base32.StdEncoding.EncodeToString(sum[:])
+}
+
+// WARCWriter write records to w, if Gzip is true, each record is written as a separate gzip member, as .warc.gz expected
+type WARCWriter struct {
+	w    io.Writer
+	Gzip bool
+}
+
+func NewWARCWriter(w io.Writer, gz bool) *WARCWriter {
+	return &WARCWriter{w: w, Gzip: gz}
+}
+
+func (ww *WARCWriter) WriteRecord(rec *WARCRecord) error {
+	if rec.Version == "" {
+		rec.Version = WARCVersion
+	}
+	if rec.Header.Get("WARC-Record-ID") == "" {
+		rec.Header.Set("WARC-Record-ID", newWARCRecordID())
+	}
+	if rec.Header.Get("WARC-Date") == "" {
+		rec.Header.Set("WARC-Date", time.Now().UTC().Format(time.RFC3339))
+	}
+	rec.Header.Set("WARC-Block-Digest", warcDigest(rec.Content))
+	rec.Header.Set("Content-Length", strconv.Itoa(len(rec.Content)))
+
+	if !ww.Gzip {
+		_, err := ww.w.Write(rec.Bytes())
+		return err
+	}
+	gw := gzip.NewWriter(ww.w)
+	if _, err := gw.Write(rec.Bytes()); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// WriteResponse write raw http response as response record
+func (ww *WARCWriter) WriteResponse(uri string, date time.Time, raw []byte) error {
+	rec := NewWARCRecord(WARCTypeResponse, raw)
+	rec.Header.Set("WARC-Target-URI", uri)
+	rec.Header.Set("Content-Type", WARCHTTPResponseType)
+	if !date.IsZero() {
+		rec.Header.Set("WARC-Date", date.UTC().Format(time.RFC3339))
+	}
+	if body, _, ok := httputils.SplitHttpRaw(raw); ok {
+		rec.Header.Set("WARC-Payload-Digest", warcDigest(body))
+	}
+	return ww.WriteRecord(rec)
+}
+
+// WriteContent write Origin, the bytes before charset transcoding, so the record matches its header and hashes.
+// Raw is written only if Origin is nil
+func (ww *WARCWriter) WriteContent(uri string, date time.Time, content *Content) error {
+	if content.Origin != nil {
+		return ww.WriteResponse(uri, date, content.Origin)
+	}
+	return ww.WriteResponse(uri, date, content.Raw)
+}
+
+// WARCReader read records one by one from .warc or .warc.gz stream
+type WARCReader struct {
+	r       *bufio.Reader
+	closers []io.Closer // closed in order, gzip reader first then the file
+}
+
+func NewWARCReader(r io.Reader) (*WARCReader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return &WARCReader{r: bufio.NewReader(gr), closers: []io.Closer{gr}}, nil
+	}
+	return &WARCReader{r: br}, nil
+}
+
+func OpenWARCFile(filename string) (*WARCReader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := NewWARCReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	reader.closers = append(reader.closers, f)
+	return reader, nil
+}
+
+// Next return the next record, io.EOF if there is no more record
+func (wr *WARCReader) Next() (*WARCRecord, error) {
+	var version string
+	for {
+		line, err := wr.r.ReadString('\n')
+		if err != nil && (err != io.EOF || strings.TrimSpace(line) == "") {
+			return nil, err
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			version = line
+			break
+		}
+	}
+	if !strings.HasPrefix(version, "WARC/") {
+		return nil, fmt.Errorf("invalid warc record version %q", version)
+	}
+
+	header, err := textproto.NewReader(wr.r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid warc record length %q", header.Get("Content-Length"))
+	} else if length > WARCMaxRecordSize {
+		return nil, fmt.Errorf("warc record length %d exceeds %d", length, WARCMaxRecordSize)
+	}
+	// the declared length is untrusted, buffer grows with the data actually read
+	var content bytes.Buffer
+	if n, err := io.CopyN(&content, wr.r, length); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("warc record truncated, %d of %d bytes read", n, length)
+		}
+		return nil, err
+	}
+	return &WARCRecord{Version: version, Header: http.Header(header), Content: content.Bytes()}, nil
+}
+
+// Responses iterate over response records, records that are not http response are skipped
+func (wr *WARCReader) Responses(fn func(record *WARCRecord, resp *Response) error) error {
+	for {
+		record, err := wr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		resp := record.Response()
+		if resp == nil {
+			continue
+		}
+		if err := fn(record, resp); err != nil {
+			return err
+		}
+	}
+}
+
+// Close close gzip reader and the opened file, the first error is returned
+func (wr *WARCReader) Close() error {
+	var firstErr error
+	for _, closer := range wr.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
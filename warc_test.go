@@ -0,0 +1,159 @@
+package parsers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+const warcTestRaw = "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<html><title>warc</title></html>"
+
+func writeWARCTest(t *testing.T, gz bool) []byte {
+	var buf bytes.Buffer
+	w := NewWARCWriter(&buf, gz)
+	if err := w.WriteRecord(NewWARCRecord(WARCTypeInfo, []byte("software: parsers\r\n"))); err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := w.WriteResponse("http://example.com/", date, []byte(warcTestRaw)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRecord(NewWARCRecord(WARCTypeMetadata, []byte("via: test\r\n"))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestWARC_RoundTrip(t *testing.T) {
+	for _, gz := range []bool{false, true} {
+		data := writeWARCTest(t, gz)
+		if gz != (data[0] == 0x1f && data[1] == 0x8b) {
+			t.Errorf("gzip %v: unexpected magic %x", gz, data[:2])
+		}
+		r, err := NewWARCReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var types []string
+		for {
+			rec, err := r.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("gzip %v: %s", gz, err)
+			}
+			types = append(types, rec.Type())
+			if rec.Type() != WARCTypeResponse {
+				continue
+			}
+			if string(rec.Content) != warcTestRaw || rec.TargetURI() != "http://example.com/" {
+				t.Errorf("gzip %v: unexpected record %q %s", gz, rec.Content, rec.TargetURI())
+			}
+			if !rec.Date().Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("gzip %v: unexpected date %s", gz, rec.Date())
+			}
+			if rec.Header.Get("WARC-Block-Digest") != warcDigest(rec.Content) {
+				t.Errorf("gzip %v: unexpected digest %s", gz, rec.Header.Get("WARC-Block-Digest"))
+			}
+		}
+		if strings.Join(types, ",") != "warcinfo,response,metadata" {
+			t.Errorf("gzip %v: unexpected records %v", gz, types)
+		}
+		r.Close()
+	}
+}
+
+func TestWARCReader_Responses(t *testing.T) {
+	r, err := NewWARCReader(bytes.NewReader(writeWARCTest(t, true)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	err = r.Responses(func(record *WARCRecord, resp *Response) error {
+		titles = append(titles, resp.Title)
+		if u := resp.URL(); u == nil || u.String() != "http://example.com/" {
+			t.Errorf("unexpected url %s", resp.URL())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(titles) != 1 || titles[0] != "warc" {
+		t.Errorf("non-response records should be skipped, got %v", titles)
+	}
+}
+
+func TestWARCWriter_WriteContent(t *testing.T) {
+	body := mustEncode(t, simplifiedchinese.GBK, "<html><title>管理后台</title></html>")
+	raw := append([]byte(fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=gbk\r\nContent-Length: %d\r\n\r\n", len(body))), body...)
+	origin := NewResponseWithRaw(raw)
+	origin.Hash()
+
+	var buf bytes.Buffer
+	if err := NewWARCWriter(&buf, false).WriteContent("http://example.com/", time.Time{}, origin.Content); err != nil {
+		t.Fatal(err)
+	}
+	r, _ := NewWARCReader(&buf)
+	rec, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := rec.Response()
+	if resp == nil || resp.Title != "管理后台" || !bytes.Equal(resp.Body, origin.Body) || resp.BodyMd5 != origin.BodyMd5 {
+		t.Errorf("unexpected round trip %q %q", resp.Title, resp.Body)
+	}
+}
+
+func TestWARCReader_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"version":  "HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n",
+		"header":   "WARC/1.1\r\nWARC-Type response\r\n\r\n",
+		"negative": "WARC/1.1\r\nWARC-Type: response\r\nContent-Length: -5\r\n\r\n",
+		"nan":      "WARC/1.1\r\nWARC-Type: response\r\nContent-Length: abc\r\n\r\n",
+		"huge":     "WARC/1.1\r\nWARC-Type: response\r\nContent-Length: 99999999999999\r\n\r\n",
+		"short":    "WARC/1.1\r\nWARC-Type: response\r\nContent-Length: 100\r\n\r\nHTTP/1.1 200 OK\r\n",
+	}
+	for name, data := range cases {
+		r, err := NewWARCReader(strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec, err := r.Next(); err == nil || err == io.EOF {
+			t.Errorf("%s: expect error, got %v %v", name, rec, err)
+		}
+	}
+}
+
+func TestOpenWARCFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "warc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "test.warc.gz")
+	if err := ioutil.WriteFile(filename, writeWARCTest(t, true), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := OpenWARCFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.closers) != 2 {
+		t.Errorf("both gzip reader and file should be closed, got %d closers", len(r.closers))
+	}
+	if rec, err := r.Next(); err != nil || rec.Type() != WARCTypeInfo {
+		t.Errorf("unexpected record %v %v", rec, err)
+	}
+	if err := r.Close(); err != nil {
+		t.Error(err)
+	}
+}